@@ -2,17 +2,22 @@ package cmd
 
 import (
 	"fmt"
+	"os/exec"
+	"runtime"
 	"strings"
 
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/spf13/cobra"
-	"github.com/zalando/go-keyring"
+	"golang.org/x/oauth2"
 )
 
 var auth_loginCommands = []string{
 	// auth_loginPassword.Use,
 	auth_loginClientCredentials.Use,
+	auth_loginCode.Use,
+	auth_loginDevice.Use,
+	auth_loginTokenExchange.Use,
 }
 
 var auth_loginCmd = &cobra.Command{
@@ -35,12 +40,15 @@ var auth_loginClientCredentials = &cobra.Command{
 		// noCache := flagHelper.GetOptionalString("noCache")
 		errMsg := fmt.Sprintf("Please provide required flag: (%s)", "Param Not Found")
 
-		// h.DEBUG_PrintKeyRingSecrets()
+		// the active profile supplies the client id when none is given
+		if clientId == "" && activeProfile != nil {
+			clientId = activeProfile.ClientId
+		}
 
 		// check if we have a clientId in the keyring, if a null value is passed in
 		if clientId == "" {
 			fmt.Println("No clientId provided. Attempting to retrieve the default from keyring.")
-			retrievedClientID, errID := keyring.Get(handlers.TOKEN_URL, handlers.OTDFCTL_CLIENT_ID_CACHE_KEY)
+			retrievedClientID, errID := h.GetClientIDFromCache()
 			if errID == nil {
 				clientId = retrievedClientID
 				fmt.Println(cli.SuccessMessage("Retrieved stored clientId from keyring"))
@@ -50,15 +58,13 @@ var auth_loginClientCredentials = &cobra.Command{
 		// now lets check if we still don't have it, and if not, throw and error
 		if clientId == "" {
 			errMsg = fmt.Sprintf("Please provide required flag: (%s)", "clientId")
-			fmt.Println(cli.ErrorMessage(errMsg, nil))
-			cli.ExitWithError("Failed to create attribute", nil)
-			return
+			cli.ExitWithError(errMsg, nil)
 		}
 
 		// check if we have a clientSecret in the keyring, if a null value is passed in
 		if clientSecret == "" {
-			retrievedSecret, krErr := keyring.Get(handlers.TOKEN_URL, clientId)
-			if krErr == nil {
+			retrievedSecret, retrievedClientID, krErr := h.GetClientIdAndSecretFromCache()
+			if krErr == nil && retrievedClientID == clientId {
 				clientSecret = retrievedSecret
 				fmt.Println(cli.SuccessMessage("Retrieved stored clientSecret from keyring"))
 			}
@@ -66,27 +72,199 @@ var auth_loginClientCredentials = &cobra.Command{
 		// check if we still don't have it, and if not throw an error
 		if clientSecret == "" {
 			errMsg = fmt.Sprintf("Please provide required flag: (%s)", "clientSecret")
-			fmt.Println(cli.ErrorMessage(errMsg, nil))
-			cli.ExitWithError("Failed to create attribute", nil)
-			return
+			cli.ExitWithError(errMsg, nil)
 		}
 
-		// for now we're hardcoding the TOKEN_URL as a constant at the top
-		_, err := h.GetTokenWithClientCredentials(clientId, clientSecret, handlers.TOKEN_URL, false)
+		_, err := h.GetTokenWithClientCredentials(clientId, clientSecret, getIdP(cmd), false)
 		if err != nil {
-			errMsg = cli.ErrorMessage("An error occurred during login. Please check your credentials and try again.", nil)
-			fmt.Println(errMsg)
-			cli.ExitWithError(errMsg, err)
-			return
+			cli.ExitWithError("An error occurred during login. Please check your credentials and try again", err)
 		}
 
 		fmt.Println(cli.SuccessMessage("Successfully logged in with clientId and clientSecret"))
 	},
 }
 
+var auth_loginCode = &cobra.Command{
+	Use:   "code",
+	Short: "Allows the user to login in a browser via the Authorization Code flow with PKCE, without holding a client secret. The access and refresh tokens are stored in the OS-specific keychain.",
+	Run: func(cmd *cobra.Command, args []string) {
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		flagHelper := cli.NewFlagHelper(cmd)
+		clientId := getPublicClientId(flagHelper)
+		port, _ := cmd.Flags().GetInt("port")
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		noBrowser := flagHelper.GetOptionalBool("no-browser")
+
+		_, err := h.GetTokenWithAuthorizationCode(handlers.AuthorizationCodeOptions{
+			ClientID: clientId,
+			IdP:      getIdP(cmd),
+			Scopes:   scopes,
+			Port:     port,
+			OpenURL: func(url string) error {
+				fmt.Printf("Log in by visiting:\n\n\t%s\n\n", url)
+				if noBrowser {
+					return nil
+				}
+				if err := openBrowser(url); err != nil {
+					fmt.Println(cli.ErrorMessage("Failed to open a browser, please visit the URL above", err))
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			cli.ExitWithError("An error occurred during login", err)
+		}
+
+		fmt.Println(cli.SuccessMessage("Successfully logged in with the authorization code flow"))
+	},
+}
+
+var auth_loginDevice = &cobra.Command{
+	Use:   "device",
+	Short: "Allows the user to login on a headless machine via the Device Authorization Grant, completing the login in a browser on another device. The access and refresh tokens are stored in the OS-specific keychain.",
+	Run: func(cmd *cobra.Command, args []string) {
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		flagHelper := cli.NewFlagHelper(cmd)
+		clientId := getPublicClientId(flagHelper)
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+
+		_, err := h.GetTokenWithDeviceCode(handlers.DeviceCodeOptions{
+			ClientID: clientId,
+			IdP:      getIdP(cmd),
+			Scopes:   scopes,
+			Prompt: func(resp *oauth2.DeviceAuthResponse) {
+				fmt.Printf("On any device, visit:\n\n\t%s\n\nand enter the code:\n\n\t%s\n\n", resp.VerificationURI, resp.UserCode)
+				if resp.VerificationURIComplete != "" {
+					fmt.Printf("Or visit the following URL, which includes the code:\n\n\t%s\n\n", resp.VerificationURIComplete)
+				}
+				fmt.Println("Waiting for the login to complete...")
+			},
+		})
+		if err != nil {
+			cli.ExitWithError("An error occurred during login", err)
+		}
+
+		fmt.Println(cli.SuccessMessage("Successfully logged in with the device code flow"))
+	},
+}
+
+var auth_loginTokenExchange = &cobra.Command{
+	Use:   "token-exchange",
+	Short: "Allows the user to act as another subject, i.e. a service account, by exchanging the token of the current login (RFC 8693). The exchanged token replaces the login in the OS-specific keychain until the next login or logout.",
+	Run: func(cmd *cobra.Command, args []string) {
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		flagHelper := cli.NewFlagHelper(cmd)
+		clientId := getPublicClientId(flagHelper)
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		subject := flagHelper.GetOptionalString("subject")
+
+		_, err := h.GetTokenWithTokenExchange(handlers.TokenExchangeOptions{
+			ClientID:         clientId,
+			ClientSecret:     flagHelper.GetOptionalString("client-secret"),
+			IdP:              getIdP(cmd),
+			RequestedSubject: subject,
+			Audience:         flagHelper.GetOptionalString("audience"),
+			Scopes:           scopes,
+		})
+		if err != nil {
+			cli.ExitWithError("An error occurred during token exchange", err)
+		}
+
+		if subject != "" {
+			fmt.Println(cli.SuccessMessage(fmt.Sprintf("Successfully logged in as %s with token exchange", subject)))
+			return
+		}
+		fmt.Println(cli.SuccessMessage("Successfully logged in with token exchange"))
+	},
+}
+
+// getPublicClientId reads the client id of an interactive login, falling back to the active profile's client id
+func getPublicClientId(flagHelper *cli.FlagHelper) string {
+	clientId := flagHelper.GetOptionalString("client-id")
+	if clientId == "" && activeProfile != nil {
+		clientId = activeProfile.ClientId
+	}
+	if clientId == "" {
+		cli.ExitWithError("Please provide required flag: (client-id)", nil)
+	}
+	return clientId
+}
+
+// getIdP reads the IdP endpoints given for a login, falling back to the issuer and token endpoint of the active
+// profile. Endpoints left empty are discovered from the issuer, or from the platform when no issuer is given.
+func getIdP(cmd *cobra.Command) handlers.OIDCConfiguration {
+	// each login command only has the endpoint flags its flow uses
+	endpoint := func(flag string) string {
+		if f := cmd.Flag(flag); f != nil {
+			return f.Value.String()
+		}
+		return ""
+	}
+	idp := handlers.OIDCConfiguration{
+		Issuer:                      endpoint("issuer"),
+		TokenEndpoint:               endpoint("token-url"),
+		AuthorizationEndpoint:       endpoint("auth-url"),
+		DeviceAuthorizationEndpoint: endpoint("device-auth-url"),
+	}
+	if activeProfile != nil {
+		if idp.Issuer == "" {
+			idp.Issuer = activeProfile.Issuer
+		}
+		if idp.TokenEndpoint == "" {
+			idp.TokenEndpoint = activeProfile.TokenEndpoint
+		}
+	}
+	return idp
+}
+
+// openBrowser opens the URL with the platform's default browser
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
+
 func init() {
+	auth_loginCmd.AddCommand(auth_loginCode)
+	auth_loginCode.Flags().StringP("client-id", "i", "", "The public client id (default is the client id of the active profile)")
+	auth_loginCode.Flags().String("issuer", "", "The IdP issuer to discover the endpoints of (default is the IdP of the platform)")
+	auth_loginCode.Flags().String("auth-url", "", "The IdP authorize endpoint (default is discovered)")
+	auth_loginCode.Flags().String("token-url", "", "The IdP token endpoint (default is discovered)")
+	auth_loginCode.Flags().StringSlice("scopes", []string{"openid"}, "The scopes to request")
+	auth_loginCode.Flags().Int("port", 0, "The port of the loopback redirect listener (default is any free port)")
+	auth_loginCode.Flags().Bool("no-browser", false, "Print the login URL instead of opening a browser")
+
+	auth_loginCmd.AddCommand(auth_loginDevice)
+	auth_loginDevice.Flags().StringP("client-id", "i", "", "The public client id (default is the client id of the active profile)")
+	auth_loginDevice.Flags().String("issuer", "", "The IdP issuer to discover the endpoints of (default is the IdP of the platform)")
+	auth_loginDevice.Flags().String("device-auth-url", "", "The IdP device authorization endpoint (default is discovered)")
+	auth_loginDevice.Flags().String("token-url", "", "The IdP token endpoint (default is discovered)")
+	auth_loginDevice.Flags().StringSlice("scopes", []string{"openid"}, "The scopes to request")
+
+	auth_loginCmd.AddCommand(auth_loginTokenExchange)
+	auth_loginTokenExchange.Flags().StringP("client-id", "i", "", "The client id permitted to exchange tokens (default is the client id of the active profile)")
+	auth_loginTokenExchange.Flags().StringP("client-secret", "s", "", "The client secret, unless the client is public")
+	auth_loginTokenExchange.Flags().String("subject", "", "The subject to impersonate, i.e. a service account (default is the logged in subject)")
+	auth_loginTokenExchange.Flags().String("audience", "", "The audience of the exchanged token")
+	auth_loginTokenExchange.Flags().StringSlice("scopes", []string{}, "The scopes to request")
+	auth_loginTokenExchange.Flags().String("issuer", "", "The IdP issuer to discover the token endpoint of (default is the IdP of the platform)")
+	auth_loginTokenExchange.Flags().String("token-url", "", "The IdP token endpoint (default is discovered)")
+
 	auth_loginCmd.AddCommand(auth_loginClientCredentials)
 	auth_loginClientCredentials.Flags().StringP("clientId", "i", "", "The client id")
 	auth_loginClientCredentials.Flags().StringP("clientSecret", "s", "", "The client secret")
+	auth_loginClientCredentials.Flags().String("issuer", "", "The IdP issuer to discover the token endpoint of (default is the IdP of the platform)")
+	auth_loginClientCredentials.Flags().String("token-url", "", "The IdP token endpoint (default is discovered)")
 	authCmd.AddCommand(auth_loginCmd)
 }
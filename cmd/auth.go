@@ -1,6 +1,15 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/spf13/cobra"
+)
 
 var (
 	// authCmd is the command for managing local authentication session (login, logout, and token caching)
@@ -9,8 +18,75 @@ var (
 		Short: "Manage local authentication session",
 		Long:  `This command will allow you to manage your local authentication session in regards to the DSP platform.`,
 	}
+
+	auth_logoutCmd = &cobra.Command{
+		Use:   "logout",
+		Short: "Log out of the platform, removing the cached tokens and client credentials of its logins with every IdP",
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			if err := h.Logout(); err != nil {
+				if errors.Is(err, handlers.ErrNotLoggedIn) {
+					fmt.Println(cli.SuccessMessage("Not logged in"))
+					return
+				}
+				cli.ExitWithError("Failed to log out", err)
+			}
+			fmt.Println(cli.SuccessMessage("Successfully logged out"))
+		},
+	}
+
+	auth_statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the cached login to the platform: client, issuer, expiry and scopes",
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			status, err := h.GetLoginStatus()
+			if err != nil {
+				cli.ExitWithError("Failed to read the login status", err)
+			}
+
+			expiry := status.Expiry.Local().Format(time.RFC3339)
+			if status.Expired {
+				expiry += " (expired)"
+			}
+			t := cli.NewTabular().Rows([][]string{
+				{"Platform", status.Platform},
+				{"Issuer", status.Issuer},
+				{"Token Endpoint", status.TokenEndpoint},
+				{"Client Id", status.ClientId},
+				{"Subject", status.Subject},
+				{"Scopes", strings.Join(status.Scopes, " ")},
+				{"Expiry", expiry},
+				{"Refreshable", fmt.Sprint(status.Refreshable)},
+			}...)
+			HandleSuccess(cmd, "", t, status)
+		},
+	}
+
+	auth_printAccessTokenCmd = &cobra.Command{
+		Use:   "print-access-token",
+		Short: "Print the access token of the cached login to stdout, refreshing it first if it has expired",
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			token, err := h.GetAccessToken()
+			if err != nil {
+				cli.ExitWithError("Failed to get an access token", err)
+			}
+			// only the token goes to stdout, so it can be used with other tools
+			fmt.Println(token)
+		},
+	}
 )
 
 func init() {
+	authCmd.AddCommand(auth_logoutCmd)
+	authCmd.AddCommand(auth_statusCmd)
+	authCmd.AddCommand(auth_printAccessTokenCmd)
 	rootCmd.AddCommand(authCmd)
 }
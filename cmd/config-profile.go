@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/internal/config"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/grpc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	config_profileCommands = []string{
+		config_profileAddCmd.Use,
+		config_profileListCmd.Use,
+		config_profileUseCmd.Use,
+		config_profileRemoveCmd.Use,
+	}
+
+	config_profileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Manage connection profiles [" + strings.Join(config_profileCommands, ", ") + "]",
+		Long: `
+Profile - commands to manage named connection profiles.
+
+A profile holds the host, TLS settings, token endpoint, client id and output format of one platform, so that
+switching platforms is a matter of 'config profile use <name>' or the global '--profile' flag rather than
+retyping hosts. Flags given explicitly on a command always take precedence over the active profile.
+
+The host and TLS settings of a new profile are given with the global connection flags, i.e.
+
+	otdfctl config profile add --name staging --host staging.example.com:443 --tls-ca-file ca.pem
+`,
+	}
+
+	config_profileAddCmd = &cobra.Command{
+		Use:   "add",
+		Short: "Add or replace a connection profile",
+		Run: func(cmd *cobra.Command, args []string) {
+			flagHelper := cli.NewFlagHelper(cmd)
+			name := flagHelper.GetRequiredString("name")
+			// the profile host is given with the global --host flag, which must be set explicitly so that the
+			// default or the current profile's host is not saved by accident
+			if !cmd.Flags().Changed("host") {
+				cli.ExitWithError("Flag host is required", nil)
+			}
+			host := flagHelper.GetRequiredString("host")
+			output := flagHelper.GetOptionalString("output")
+			// the active profile fills in the TLS flags that were not given, so only explicit ones are saved
+			tlsConfig := grpc.TLSConfig{
+				Plaintext:          explicitFlag(cmd, "plaintext") == "true",
+				CAFile:             explicitFlag(cmd, "tls-ca-file"),
+				CertFile:           explicitFlag(cmd, "tls-cert-file"),
+				KeyFile:            explicitFlag(cmd, "tls-key-file"),
+				ServerName:         explicitFlag(cmd, "tls-server-name"),
+				InsecureSkipVerify: explicitFlag(cmd, "insecure-skip-verify") == "true",
+			}
+			if _, err := tlsConfig.Load(); err != nil {
+				cli.ExitWithError("Invalid TLS settings", err)
+			}
+
+			if output != "" {
+				format, err := cli.ParseOutputFormat(output)
+				if err != nil {
+					cli.ExitWithError("Invalid output format", err)
+				}
+				output = format.String()
+			}
+
+			p := config.Profile{
+				Host: host,
+				TLS: config.TLS{
+					Plaintext:          tlsConfig.Plaintext,
+					CAFile:             tlsConfig.CAFile,
+					CertFile:           tlsConfig.CertFile,
+					KeyFile:            tlsConfig.KeyFile,
+					ServerName:         tlsConfig.ServerName,
+					InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+				},
+				Issuer:        flagHelper.GetOptionalString("issuer"),
+				TokenEndpoint: flagHelper.GetOptionalString("token-endpoint"),
+				ClientId:      flagHelper.GetOptionalString("client-id"),
+				Output:        config.Output{Format: output},
+			}
+			if err := OtdfctlCfg.AddProfile(name, p); err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to add profile (%s)", name), err)
+			}
+			fmt.Println(cli.SuccessMessage(fmt.Sprintf("Added profile %s", strings.ToLower(name))))
+		},
+	}
+
+	config_profileListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List connection profiles",
+		Run: func(cmd *cobra.Command, args []string) {
+			names := make([]string, 0, len(OtdfctlCfg.Profiles))
+			for name := range OtdfctlCfg.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			t := cli.NewTable()
+			t.Headers("Current", "Name", "Host", "TLS", "Token Endpoint", "Client Id", "Output")
+			for _, name := range names {
+				p := OtdfctlCfg.Profiles[name]
+				current := ""
+				if name == OtdfctlCfg.CurrentProfile {
+					current = "*"
+				}
+				tls := "enabled"
+				if p.TLS.Plaintext {
+					tls = "plaintext"
+				} else if p.TLS.InsecureSkipVerify {
+					tls = "unverified"
+				} else if p.TLS.CertFile != "" {
+					tls = "mTLS"
+				}
+				t.Row(current, name, p.Host, tls, p.TokenEndpoint, p.ClientId, p.Output.Format)
+			}
+			HandleSuccess(cmd, "", t, OtdfctlCfg.Profiles)
+		},
+	}
+
+	config_profileUseCmd = &cobra.Command{
+		Use:   "use",
+		Short: "Set the current connection profile",
+		Run: func(cmd *cobra.Command, args []string) {
+			flagHelper := cli.NewFlagHelper(cmd)
+			name := flagHelper.GetRequiredString("name")
+
+			if err := OtdfctlCfg.UseProfile(name); err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to use profile (%s)", name), err)
+			}
+			fmt.Println(cli.SuccessMessage(fmt.Sprintf("Now using profile %s", strings.ToLower(name))))
+		},
+	}
+
+	config_profileRemoveCmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a connection profile",
+		Run: func(cmd *cobra.Command, args []string) {
+			flagHelper := cli.NewFlagHelper(cmd)
+			name := flagHelper.GetRequiredString("name")
+
+			if err := OtdfctlCfg.RemoveProfile(name); err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to remove profile (%s)", name), err)
+			}
+			fmt.Println(cli.SuccessMessage(fmt.Sprintf("Removed profile %s", strings.ToLower(name))))
+		},
+	}
+)
+
+// Returns the value of a flag given on the command line, ignoring values filled in from the active profile
+func explicitFlag(cmd *cobra.Command, flag string) string {
+	if !cmd.Flags().Changed(flag) {
+		return ""
+	}
+	return cmd.Flag(flag).Value.String()
+}
+
+func init() {
+	configCmd.AddCommand(config_profileCmd)
+
+	config_profileCmd.AddCommand(config_profileAddCmd)
+	config_profileAddCmd.Flags().StringP("name", "n", "", "Name of the profile")
+	config_profileAddCmd.Flags().String("issuer", "", "OIDC issuer of the identity provider to log in with (default is the one the platform advertises)")
+	config_profileAddCmd.Flags().String("token-endpoint", "", "OIDC token endpoint of the platform's identity provider")
+	config_profileAddCmd.Flags().String("client-id", "", "Client id used to authenticate to the platform")
+
+	config_profileCmd.AddCommand(config_profileListCmd)
+
+	config_profileCmd.AddCommand(config_profileUseCmd)
+	config_profileUseCmd.Flags().StringP("name", "n", "", "Name of the profile")
+
+	config_profileCmd.AddCommand(config_profileRemoveCmd)
+	config_profileRemoveCmd.Flags().StringP("name", "n", "", "Name of the profile")
+}
@@ -24,23 +24,29 @@ var updateOutputFormatCmd = &cobra.Command{
 	Use:   "output",
 	Short: "Define the configured output format",
 	Long: `
-Define the configured output format for the 'otdfctl' command line tool. The only supported outputs at
-this time are 'json' and styled CLI output, which is the default when unspecified.
+Define the configured output format for the 'otdfctl' command line tool. The supported outputs are styled
+CLI output, which is the default when unspecified, 'json', 'yaml', 'csv', 'ndjson', 'template=<go-template>'
+and 'jsonpath=<expr>', the same as the global --output flag.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		h := cli.NewHandler(cmd)
 		defer h.Close()
 
 		flagHelper := cli.NewFlagHelper(cmd)
-		format := flagHelper.GetRequiredString("format")
-
-		config.UpdateOutputFormat(format)
+		format, err := cli.ParseOutputFormat(flagHelper.GetRequiredString("format"))
+		if err != nil {
+			cli.ExitWithError("Invalid output format", err)
+		}
+
+		if err := config.UpdateOutputFormat(format.String()); err != nil {
+			cli.ExitWithError("Failed to update the output format", err)
+		}
 		fmt.Println(cli.SuccessMessage(fmt.Sprintf("Output format updated to %s", format)))
 	},
 }
 
 func init() {
-	updateOutputFormatCmd.Flags().String("format", "", "'json' or 'styled' as the configured output format")
+	updateOutputFormatCmd.Flags().String("format", "", "The configured output format: "+cli.OutputFormatHelp)
 	configCmd.AddCommand(updateOutputFormatCmd)
 	rootCmd.AddCommand(configCmd)
 }
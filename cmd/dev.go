@@ -4,10 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/charmbracelet/lipgloss/table"
-	"github.com/opentdf/otdfctl/internal/config"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/opentdf/platform/protocol/go/common"
 	"github.com/spf13/cobra"
 )
@@ -79,19 +80,27 @@ func unMarshalMetadata(m string) *common.MetadataMutable {
 }
 
 func getMetadataMutable(labels []string) *common.MetadataMutable {
+	metadata, err := parseMetadataLabels(labels)
+	if err != nil {
+		cli.ExitWithError("Invalid label format", nil)
+	}
+	return metadata
+}
+
+func parseMetadataLabels(labels []string) (*common.MetadataMutable, error) {
 	metadata := common.MetadataMutable{}
 	if len(labels) > 0 {
 		metadata.Labels = map[string]string{}
 		for _, label := range labels {
 			kv := strings.Split(label, "=")
 			if len(kv) != 2 {
-				cli.ExitWithError("Invalid label format", nil)
+				return nil, fmt.Errorf("invalid label '%s', must be in the format key=value", label)
 			}
 			metadata.Labels[kv[0]] = kv[1]
 		}
-		return &metadata
+		return &metadata, nil
 	}
-	return nil
+	return nil, nil
 }
 
 func getMetadataUpdateBehavior() common.MetadataUpdateEnum {
@@ -103,17 +112,110 @@ func getMetadataUpdateBehavior() common.MetadataUpdateEnum {
 
 // HandleSuccess prints a success message according to the configured format (styled table or JSON)
 func HandleSuccess(command *cobra.Command, id string, t *table.Table, policyObject interface{}) {
-	if OtdfctlCfg.Output.Format == config.OutputJSON || configFlagOverrides.OutputFormatJSON {
-		if output, err := json.MarshalIndent(policyObject, "", "  "); err != nil {
-			cli.ExitWithError("Error marshalling policy object", err)
-		} else {
-			fmt.Println(string(output))
+	if format := outputFormat(command); format.Kind != cli.OutputStyled {
+		if err := cli.PrintOutput(format, policyObject); err != nil {
+			cli.ExitWithError("Error printing "+format.Kind+" output", err)
 		}
 		return
 	}
 	cli.PrintSuccessTable(command, id, t)
 }
 
+// Applies the global list flags to the rows of a list table, then renders the selected page of the table, or of
+// the items the rows were built from as JSON
+func handleListSuccess[T any](command *cobra.Command, t *table.Table, headers []string, rows [][]string, items []T) {
+	page := cli.PageList(cli.GetListOptions(command), headers, rows)
+	t.Headers(headers...).Rows(page.Rows...)
+	HandleSuccess(command, "", t, cli.PageItems(items, page))
+	if outputFormat(command).Kind == cli.OutputStyled {
+		fmt.Println(cli.FooterMessage(page.Footer()))
+	}
+}
+
+// Resolves a flag that takes an ID or, depending on the kind of object, a name, FQN or URI. Empty stays empty, so
+// optional flags can be resolved the same way.
+func resolveId(kind string, resolve func(string) (string, error), ref string) string {
+	if ref == "" {
+		return ""
+	}
+	id, err := resolve(ref)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find %s '%s'", kind, ref), err)
+	}
+	return id
+}
+
+// Adds the flags of a create command to create many objects from a CSV or NDJSON file instead of the other flags
+func injectBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from-file", "", "CSV (with a header row) or NDJSON file of objects to create, with fields named like the flags")
+	cmd.Flags().Int("concurrency", 4, "maximum number of objects created at a time with --from-file")
+	cmd.Flags().Bool("continue-on-error", false, "keep creating the remaining objects of --from-file after one fails")
+}
+
+// Creates every record of the --from-file file and reports the outcome of each row. Returns whether a file was
+// given, as the command otherwise creates the single object of its flags, and whether any row failed.
+func handleBatchCreate(command *cobra.Command, create func(cli.BatchRecord) (string, error)) (handled, failed bool) {
+	file, _ := command.Flags().GetString("from-file")
+	if file == "" {
+		return false, false
+	}
+	concurrency, _ := command.Flags().GetInt("concurrency")
+	continueOnError, _ := command.Flags().GetBool("continue-on-error")
+
+	records, err := cli.ReadBatchFile(file)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to read %s", file), err)
+	}
+	results := cli.RunBatch(records, concurrency, continueOnError, create)
+
+	counts := map[string]int{}
+	rows := [][]string{}
+	for _, r := range results {
+		counts[r.Status]++
+		rows = append(rows, []string{fmt.Sprint(r.Line), r.Status, r.Id, r.Error})
+	}
+	if format := outputFormat(command); format.Kind != cli.OutputStyled {
+		if err := cli.PrintOutput(format, results); err != nil {
+			cli.ExitWithError("Error printing "+format.Kind+" output", err)
+		}
+	} else {
+		fmt.Println(cli.NewTable().Headers("Line", "Status", "Id", "Error").Rows(rows...).Render())
+		summary := fmt.Sprintf("%d succeeded, %d failed, %d skipped", counts[cli.BatchSucceeded], counts[cli.BatchFailed], counts[cli.BatchSkipped])
+		if counts[cli.BatchPlanned] > 0 {
+			summary += fmt.Sprintf(", %d planned (dry run)", counts[cli.BatchPlanned])
+		}
+		fmt.Println(cli.FooterMessage(summary))
+	}
+	return true, counts[cli.BatchFailed] > 0
+}
+
+// Exits with an error when a row of the --from-file file failed. The connection is closed first, as exiting skips
+// the command's deferred close.
+func exitOnBatchFailure(h handlers.Handler, failed bool) {
+	if failed {
+		h.Close()
+		cli.ExitWithError("Failed to create every object of the file", nil)
+	}
+}
+
+// Memoizes the lookups of a batch, as its rows commonly reference the same parent objects
+func cachedResolver(resolve func(string) (string, error)) func(string) (string, error) {
+	var mu sync.Mutex
+	ids := map[string]string{}
+	return func(ref string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if id, ok := ids[ref]; ok {
+			return id, nil
+		}
+		id, err := resolve(ref)
+		if err == nil {
+			ids[ref] = id
+		}
+		return id, err
+	}
+}
+
 // Adds reusable create/update label flags to a Policy command and the optional force-replace-labels flag for updates only
 func injectLabelFlags(cmd *cobra.Command, isUpdate bool) {
 	cmd.Flags().StringSliceVarP(&metadataLabels, "label", "l", []string{}, "Optional metadata 'labels' in the format: key=value")
@@ -29,9 +29,9 @@ var (
 
 			flagHelper := cli.NewFlagHelper(cmd)
 
-			attr := flagHelper.GetOptionalString("attribute")
-			val := flagHelper.GetOptionalString("value")
-			kas := flagHelper.GetRequiredString("kas")
+			attr := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetOptionalString("attribute"))
+			val := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetOptionalString("value"))
+			kas := resolveId("KAS registry entry", h.ResolveKasRegistryEntryId, flagHelper.GetRequiredString("kas"))
 
 			if attr == "" && val == "" {
 				cli.ExitWithError("Must specify and Attribute Definition id or Value id to update.", nil)
@@ -76,9 +76,9 @@ var (
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			attr := flagHelper.GetOptionalString("attribute")
-			val := flagHelper.GetOptionalString("value")
-			kas := flagHelper.GetRequiredString("kas")
+			attr := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetOptionalString("attribute"))
+			val := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetOptionalString("value"))
+			kas := resolveId("KAS registry entry", h.ResolveKasRegistryEntryId, flagHelper.GetRequiredString("kas"))
 
 			if attr == "" && val == "" {
 				cli.ExitWithError("Must specify and Attribute Definition id or Value id to delete.", nil)
@@ -90,7 +90,9 @@ var (
 				err    error
 			)
 
-			cli.ConfirmAction(cli.ActionDelete, "KAS ID: ", kas)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDelete, "KAS ID: ", kas)
+			}
 
 			if attr != "" {
 				res, err = h.DeleteKasGrantFromAttribute(attr, kas)
@@ -122,13 +124,13 @@ func init() {
 	policyCmd.AddCommand(kasGrantsCmd)
 
 	kasGrantsCmd.AddCommand(kasGrantsUpdateCmd)
-	kasGrantsUpdateCmd.Flags().StringP("attribute", "a", "", "Attribute Definition ID")
-	kasGrantsUpdateCmd.Flags().StringP("value", "v", "", "Attribute Value ID")
-	kasGrantsUpdateCmd.Flags().StringP("kas", "k", "", "Key Access Server (KAS) ID")
+	kasGrantsUpdateCmd.Flags().StringP("attribute", "a", "", "Attribute Definition ID or FQN")
+	kasGrantsUpdateCmd.Flags().StringP("value", "v", "", "Attribute Value ID or FQN")
+	kasGrantsUpdateCmd.Flags().StringP("kas", "k", "", "Key Access Server (KAS) ID or URI")
 	injectLabelFlags(kasGrantsUpdateCmd, true)
 
 	kasGrantsCmd.AddCommand(kasGrantsDeleteCmd)
-	kasGrantsDeleteCmd.Flags().StringP("attribute", "a", "", "Attribute Definition ID")
-	kasGrantsDeleteCmd.Flags().StringP("value", "v", "", "Attribute Value ID")
-	kasGrantsDeleteCmd.Flags().StringP("kas", "k", "", "Key Access Server (KAS) ID")
+	kasGrantsDeleteCmd.Flags().StringP("attribute", "a", "", "Attribute Definition ID or FQN")
+	kasGrantsDeleteCmd.Flags().StringP("value", "v", "", "Attribute Value ID or FQN")
+	kasGrantsDeleteCmd.Flags().StringP("kas", "k", "", "Key Access Server (KAS) ID or URI")
 }
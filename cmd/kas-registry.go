@@ -40,7 +40,7 @@ var (
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("KAS registry entry", h.ResolveKasRegistryEntryId, flagHelper.GetRequiredString("id"))
 
 			kas, err := h.GetKasRegistryEntry(id)
 			if err != nil {
@@ -79,8 +79,7 @@ var (
 				cli.ExitWithError("Failed to list KAS registry entries", err)
 			}
 
-			t := cli.NewTable()
-			t.Headers("Id", "URI", "PublicKey Location", "PublicKey")
+			rows := [][]string{}
 			for _, kas := range list {
 				keyType := "Local"
 				key := kas.PublicKey.GetLocal()
@@ -89,15 +88,15 @@ var (
 					key = kas.PublicKey.GetRemote()
 				}
 
-				t.Row(
+				rows = append(rows, []string{
 					kas.Id,
 					kas.Uri,
 					keyType,
 					key,
 					// TODO: render labels [https://github.com/opentdf/otdfctl/issues/73]
-				)
+				})
 			}
-			HandleSuccess(cmd, "", t, list)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "URI", "PublicKey Location", "PublicKey"}, rows, list)
 		},
 	}
 
@@ -164,7 +163,7 @@ var (
 
 			flagHelper := cli.NewFlagHelper(cmd)
 
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("KAS registry entry", h.ResolveKasRegistryEntryId, flagHelper.GetRequiredString("id"))
 			uri := flagHelper.GetOptionalString("uri")
 			local := flagHelper.GetOptionalString("public-key-local")
 			remote := flagHelper.GetOptionalString("public-key-remote")
@@ -213,7 +212,7 @@ var (
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("KAS registry entry", h.ResolveKasRegistryEntryId, flagHelper.GetRequiredString("id"))
 
 			kas, err := h.GetKasRegistryEntry(id)
 			if err != nil {
@@ -221,7 +220,9 @@ var (
 				cli.ExitWithError(errMsg, err)
 			}
 
-			cli.ConfirmAction(cli.ActionDelete, "KAS Registry Entry: ", id)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDelete, "KAS Registry Entry: ", id, getDependents(h.KasRegistryEntryDependents, id)...)
+			}
 
 			if _, err := h.DeleteKasRegistryEntry(id); err != nil {
 				errMsg := fmt.Sprintf("Failed to delete KAS registry entry (%s)", id)
@@ -243,7 +244,7 @@ func init() {
 	policyCmd.AddCommand(kasRegistryCmd)
 
 	kasRegistryCmd.AddCommand(kasRegistryGetCmd)
-	kasRegistryGetCmd.Flags().StringP("id", "i", "", "Id of the KAS registry entry")
+	kasRegistryGetCmd.Flags().StringP("id", "i", "", "Id or URI of the KAS registry entry")
 
 	kasRegistryCmd.AddCommand(kasRegistrysListCmd)
 	// TODO: active, inactive, any state querying [https://github.com/opentdf/otdfctl/issues/68]
@@ -255,12 +256,12 @@ func init() {
 	injectLabelFlags(kasRegistrysCreateCmd, false)
 
 	kasRegistryCmd.AddCommand(kasRegistryUpdateCmd)
-	kasRegistryUpdateCmd.Flags().StringP("id", "i", "", "Id of the KAS registry entry")
+	kasRegistryUpdateCmd.Flags().StringP("id", "i", "", "Id or URI of the KAS registry entry")
 	kasRegistryUpdateCmd.Flags().StringP("uri", "u", "", "The URI of the KAS registry entry")
 	kasRegistryUpdateCmd.Flags().StringP("public-key-local", "p", "", "A local public key for the registered Key Access Server (KAS)")
 	kasRegistryUpdateCmd.Flags().StringP("public-key-remote", "r", "", "A remote endpoint that serves a public key for the registered Key Access Server (KAS)")
 	injectLabelFlags(kasRegistryUpdateCmd, true)
 
 	kasRegistryCmd.AddCommand(kasRegistryDeleteCmd)
-	kasRegistryDeleteCmd.Flags().StringP("id", "i", "", "Id of the KAS registry entry")
+	kasRegistryDeleteCmd.Flags().StringP("id", "i", "", "Id or URI of the KAS registry entry")
 }
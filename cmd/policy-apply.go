@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+var policy_applyCmd = &cobra.Command{
+	Use:   cli.ActionApply,
+	Short: "Apply a declarative policy manifest (YAML or JSON)",
+	Long: `
+Apply - reconcile a declarative policy manifest against the platform.
+
+The manifest describes namespaces, attributes with their values, subject condition sets, subject mappings,
+resource mappings, KAS registry entries and KAS grants. Objects reference each other by FQN, name or URI
+instead of by id, i.e. 'https://example.com/attr/classification/value/secret'. Subject condition sets are
+given a local name within the manifest so that subject mappings can refer to them.
+
+Missing objects are created, objects with differing labels, actions, terms or keys are updated, and objects
+marked 'active: false' are deactivated. Objects on the platform that are not in the manifest are left untouched.
+
+Resource mappings have no name, so each is matched to the mapping of its value with the same terms, otherwise to
+the only mapping of the value, or the only one sharing a term. When several mappings could match, apply stops
+with an error rather than creating a duplicate.
+
+Example manifest:
+
+	namespaces:
+	  - name: example.com
+	attributes:
+	  - namespace: example.com
+	    name: classification
+	    rule: HIERARCHY
+	    values: [topsecret, secret, confidential]
+	subjectConditionSets:
+	  - name: engineering
+	    subjectSets:
+	      - conditionGroups:
+	          - booleanOperator: AND
+	            conditions:
+	              - subjectExternalField: department
+	                operator: IN
+	                subjectExternalValues: [engineering]
+	subjectMappings:
+	  - attributeValue: https://example.com/attr/classification/value/secret
+	    subjectConditionSet: engineering
+	    actions:
+	      standard: [DECRYPT]
+	resourceMappings:
+	  - attributeValue: https://example.com/attr/classification/value/secret
+	    terms: [secret, classified]
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flagHelper := cli.NewFlagHelper(cmd)
+		file := flagHelper.GetRequiredString("file")
+
+		m, err := manifest.Load(file)
+		if err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to load policy manifest (%s)", file), err)
+		}
+
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		if h.DryRun {
+			cli.ExitWithError("Apply does not support --dry-run. Preview a manifest with 'policy diff --from <host> --to <manifest>'", nil)
+		}
+
+		results, err := h.ApplyManifest(m)
+
+		t := cli.NewTable()
+		t.Headers("Kind", "Object", "Result", "Id")
+		for _, r := range results {
+			t.Row(r.Kind, r.Object, r.Result, r.Id)
+		}
+		if err != nil {
+			if len(results) > 0 {
+				fmt.Println(t.Render())
+			}
+			cli.ExitWithError("Failed to apply policy manifest", err)
+		}
+		HandleSuccess(cmd, "", t, results)
+	},
+}
+
+func init() {
+	policyCmd.AddCommand(policy_applyCmd)
+	policy_applyCmd.Flags().StringP("file", "f", "", "Path to the YAML or JSON policy manifest")
+}
@@ -20,15 +20,39 @@ var (
 		Use:   "create",
 		Short: "Create an attribute value",
 		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			resolveAttribute := cachedResolver(h.ResolveAttributeId)
+			if handled, failed := handleBatchCreate(cmd, func(r cli.BatchRecord) (string, error) {
+				attrId, err := r.RequiredString("attribute-id")
+				if err != nil {
+					return "", err
+				}
+				if attrId, err = resolveAttribute(attrId); err != nil {
+					return "", err
+				}
+				value, err := r.RequiredString("value")
+				if err != nil {
+					return "", err
+				}
+				metadata, err := parseMetadataLabels(r.Strings("label"))
+				if err != nil {
+					return "", err
+				}
+				v, err := h.CreateAttributeValue(attrId, value, metadata)
+				return v.GetId(), err
+			}); handled {
+				exitOnBatchFailure(h, failed)
+				return
+			}
+
 			flagHelper := cli.NewFlagHelper(cmd)
-			attrId := flagHelper.GetRequiredString("attribute-id")
+			attrId := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetRequiredString("attribute-id"))
 			value := flagHelper.GetRequiredString("value")
 			metadataLabels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
 			// TODO: support create with members when update is unblocked to remove/alter them after creation [https://github.com/opentdf/platform/issues/476]
 
-			h := cli.NewHandler(cmd)
-			defer h.Close()
-
 			attr, err := h.GetAttribute(attrId)
 			if err != nil {
 				cli.ExitWithError(fmt.Sprintf("Failed to get parent attribute (%s)", attrId), err)
@@ -47,12 +71,12 @@ var (
 		Use:   "get",
 		Short: "Get an attribute value",
 		Run: func(cmd *cobra.Command, args []string) {
-			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
-
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetRequiredString("id"))
+
 			v, err := h.GetAttributeValue(id)
 			if err != nil {
 				cli.ExitWithError("Failed to find attribute value", err)
@@ -69,24 +93,23 @@ var (
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 			flagHelper := cli.NewFlagHelper(cmd)
-			attrId := flagHelper.GetRequiredString("attribute-id")
+			attrId := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetRequiredString("attribute-id"))
 			state := cli.GetState(cmd)
 			vals, err := h.ListAttributeValues(attrId, state)
 			if err != nil {
 				cli.ExitWithError("Failed to list attribute values", err)
 			}
-			t := cli.NewTable()
-			t.Headers("Id", "Fqn", "Members", "Active")
+			rows := [][]string{}
 			for _, val := range vals {
 				v := cli.GetSimpleAttributeValue(val)
-				t.Row(
+				rows = append(rows, []string{
 					v.Id,
 					v.FQN,
 					cli.CommaSeparated(v.Members),
 					v.Active,
-				)
+				})
 			}
-			HandleSuccess(cmd, "", t, vals)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "Fqn", "Members", "Active"}, rows, vals)
 		},
 	}
 
@@ -94,13 +117,13 @@ var (
 		Use:   "update",
 		Short: "Update an attribute value",
 		Run: func(cmd *cobra.Command, args []string) {
-			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
-			metadataLabels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
-
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetRequiredString("id"))
+			metadataLabels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
+
 			_, err := h.GetAttributeValue(id)
 			if err != nil {
 				cli.ExitWithError(fmt.Sprintf("Failed to get attribute value (%s)", id), err)
@@ -119,18 +142,20 @@ var (
 		Use:   "deactivate",
 		Short: "Deactivate an attribute value",
 		Run: func(cmd *cobra.Command, args []string) {
-			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
-
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetRequiredString("id"))
+
 			value, err := h.GetAttributeValue(id)
 			if err != nil {
 				cli.ExitWithError(fmt.Sprintf("Failed to get attribute value (%s)", id), err)
 			}
 
-			cli.ConfirmAction(cli.ActionDeactivate, "attribute value", value.Value)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDeactivate, "attribute value", value.Value, getDependents(h.AttributeValueDependents, id)...)
+			}
 
 			deactivated, err := h.DeactivateAttributeValue(id)
 			if err != nil {
@@ -284,23 +309,24 @@ func init() {
 	)
 
 	policy_attributeValuesCmd.AddCommand(policy_attributeValuesCreateCmd)
-	policy_attributeValuesCreateCmd.Flags().StringP("attribute-id", "a", "", "Attribute id")
+	policy_attributeValuesCreateCmd.Flags().StringP("attribute-id", "a", "", "Attribute id or FQN")
 	policy_attributeValuesCreateCmd.Flags().StringP("value", "v", "", "Value")
 	injectLabelFlags(policy_attributeValuesCreateCmd, false)
+	injectBatchFlags(policy_attributeValuesCreateCmd)
 
 	policy_attributeValuesCmd.AddCommand(policy_attributeValuesGetCmd)
-	policy_attributeValuesGetCmd.Flags().StringP("id", "i", "", "Attribute value id")
+	policy_attributeValuesGetCmd.Flags().StringP("id", "i", "", "Attribute value id or FQN")
 
 	policy_attributeValuesCmd.AddCommand(policy_attributeValuesListCmd)
-	policy_attributeValuesListCmd.Flags().StringP("attribute-id", "a", "", "Attribute id")
+	policy_attributeValuesListCmd.Flags().StringP("attribute-id", "a", "", "Attribute id or FQN")
 	policy_attributeValuesListCmd.Flags().StringP("state", "s", "active", "Filter by state [active, inactive, any]")
 
 	policy_attributeValuesCmd.AddCommand(policy_attributeValuesUpdateCmd)
-	policy_attributeValuesUpdateCmd.Flags().StringP("id", "i", "", "Attribute value id")
+	policy_attributeValuesUpdateCmd.Flags().StringP("id", "i", "", "Attribute value id or FQN")
 	injectLabelFlags(policy_attributeValuesUpdateCmd, true)
 
 	policy_attributeValuesCmd.AddCommand(policy_attributeValuesDeactivateCmd)
-	policy_attributeValuesDeactivateCmd.Flags().StringP("id", "i", "", "Attribute value id")
+	policy_attributeValuesDeactivateCmd.Flags().StringP("id", "i", "", "Attribute value id or FQN")
 
 	// Attribute value members
 	// policy_attributeValuesCmd.AddCommand(policy_attributeValueMembersCmd)
@@ -43,11 +43,39 @@ used to define the access controls based on subject encodings and entity entitle
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			resolveNamespace := cachedResolver(h.ResolveNamespaceId)
+			if handled, failed := handleBatchCreate(cmd, func(r cli.BatchRecord) (string, error) {
+				name, err := r.RequiredString("name")
+				if err != nil {
+					return "", err
+				}
+				rule, err := r.RequiredString("rule")
+				if err != nil {
+					return "", err
+				}
+				namespace, err := r.RequiredString("namespace")
+				if err != nil {
+					return "", err
+				}
+				if namespace, err = resolveNamespace(namespace); err != nil {
+					return "", err
+				}
+				metadata, err := parseMetadataLabels(r.Strings("label"))
+				if err != nil {
+					return "", err
+				}
+				attr, err := h.CreateAttribute(name, rule, namespace, r.Strings("value"), metadata)
+				return attr.GetId(), err
+			}); handled {
+				exitOnBatchFailure(h, failed)
+				return
+			}
+
 			flagHelper := cli.NewFlagHelper(cmd)
 			name := flagHelper.GetRequiredString("name")
 			rule := flagHelper.GetRequiredString("rule")
 			values := flagHelper.GetStringSlice("value", attrValues, cli.FlagHelperStringSliceOptions{})
-			namespace := flagHelper.GetRequiredString("namespace")
+			namespace := resolveId("namespace", h.ResolveNamespaceId, flagHelper.GetRequiredString("namespace"))
 			metadataLabels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
 
 			attr, err := h.CreateAttribute(name, rule, namespace, values, getMetadataMutable(metadataLabels))
@@ -79,12 +107,12 @@ used to define the access controls based on subject encodings and entity entitle
 		Use:   "get",
 		Short: "Get an attribute",
 		Run: func(cmd *cobra.Command, args []string) {
-			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
-
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetRequiredString("id"))
+
 			attr, err := h.GetAttribute(id)
 			if err != nil {
 				errMsg := fmt.Sprintf("Failed to get attribute (%s)", id)
@@ -118,20 +146,19 @@ used to define the access controls based on subject encodings and entity entitle
 				cli.ExitWithError("Failed to list attributes", err)
 			}
 
-			t := cli.NewTable()
-			t.Headers("Id", "Namespace", "Name", "Rule", "Values", "Active")
+			rows := [][]string{}
 			for _, attr := range attrs {
 				a := cli.GetSimpleAttribute(attr)
-				t.Row(
+				rows = append(rows, []string{
 					a.Id,
 					a.Namespace,
 					a.Name,
 					a.Rule,
 					cli.CommaSeparated(a.Values),
 					a.Active,
-				)
+				})
 			}
-			HandleSuccess(cmd, "", t, attrs)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "Namespace", "Name", "Rule", "Values", "Active"}, rows, attrs)
 		},
 	}
 
@@ -139,19 +166,21 @@ used to define the access controls based on subject encodings and entity entitle
 		Use:   "deactivate",
 		Short: "Deactivate an attribute",
 		Run: func(cmd *cobra.Command, args []string) {
-			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
-
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetRequiredString("id"))
+
 			attr, err := h.GetAttribute(id)
 			if err != nil {
 				errMsg := fmt.Sprintf("Failed to get attribute (%s)", id)
 				cli.ExitWithError(errMsg, err)
 			}
 
-			cli.ConfirmAction(cli.ActionDeactivate, "attribute", attr.Name)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDeactivate, "attribute", attr.Name, getDependents(h.AttributeDependents, id)...)
+			}
 
 			attr, err = h.DeactivateAttribute(id)
 			if err != nil {
@@ -180,7 +209,7 @@ used to define the access controls based on subject encodings and entity entitle
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("attribute", h.ResolveAttributeId, flagHelper.GetRequiredString("id"))
 			labels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
 
 			if a, err := h.UpdateAttribute(id, getMetadataMutable(labels), getMetadataUpdateBehavior()); err != nil {
@@ -200,12 +229,13 @@ func init() {
 	policy_attributesCreateCmd.Flags().StringP("name", "n", "", "Name of the attribute")
 	policy_attributesCreateCmd.Flags().StringP("rule", "r", "", "Rule of the attribute")
 	policy_attributesCreateCmd.Flags().StringSliceVarP(&attrValues, "value", "v", []string{}, "Values of the attribute")
-	policy_attributesCreateCmd.Flags().StringP("namespace", "s", "", "Namespace of the attribute")
+	policy_attributesCreateCmd.Flags().StringP("namespace", "s", "", "Namespace of the attribute, by id or name")
 	injectLabelFlags(policy_attributesCreateCmd, false)
+	injectBatchFlags(policy_attributesCreateCmd)
 
 	// Get an attribute
 	policy_attributesCmd.AddCommand(policy_attributeGetCmd)
-	policy_attributeGetCmd.Flags().StringP("id", "i", "", "Id of the attribute")
+	policy_attributeGetCmd.Flags().StringP("id", "i", "", "Id or FQN of the attribute")
 
 	// List attributes
 	policy_attributesCmd.AddCommand(policy_attributesListCmd)
@@ -213,10 +243,10 @@ func init() {
 
 	// Update an attribute
 	policy_attributesCmd.AddCommand(policy_attributeUpdateCmd)
-	policy_attributeUpdateCmd.Flags().StringP("id", "i", "", "Id of the attribute")
+	policy_attributeUpdateCmd.Flags().StringP("id", "i", "", "Id or FQN of the attribute")
 	injectLabelFlags(policy_attributeUpdateCmd, true)
 
 	// Deactivate an attribute
 	policy_attributesCmd.AddCommand(policy_attributesDeactivateCmd)
-	policy_attributesDeactivateCmd.Flags().StringP("id", "i", "", "Id of the attribute")
+	policy_attributesDeactivateCmd.Flags().StringP("id", "i", "", "Id or FQN of the attribute")
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/spf13/cobra"
+)
+
+var policy_decideResourceAttrs []string
+
+var policy_decideCmd = &cobra.Command{
+	Use:   "decide",
+	Short: "Simulate an access decision for an entity and resource attributes",
+	Long: `
+Decide - simulate on the client whether an entity would be granted an action on a resource with the given attribute
+values, explaining the outcome of every attribute rule.
+
+The entitlements of the entity are those of 'policy entitlements simulate' that allow the action. The resource values
+are grouped by attribute definition, and each definition's rule must be satisfied:
+  ALL_OF     the entity must be entitled to every resource value
+  ANY_OF     the entity must be entitled to at least one resource value
+  HIERARCHY  the entity must be entitled to the highest resource value or a value above it, in the value order
+             of the attribute definition
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		flagHelper := cli.NewFlagHelper(cmd)
+		entityFile := flagHelper.GetRequiredString("entity")
+		resourceAttrs := flagHelper.GetStringSlice("resource-attrs", policy_decideResourceAttrs, cli.FlagHelperStringSliceOptions{Min: 1})
+		action := flagHelper.GetRequiredString("action")
+
+		entity, err := handlers.LoadEntity(entityFile)
+		if err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to read entity from %s", entityFile), err)
+		}
+
+		decision, err := h.Decide(entity, resourceAttrs, action)
+		if err != nil {
+			cli.ExitWithError("Failed to decide access", err)
+		}
+
+		rows := [][]string{}
+		for _, r := range decision.Rules {
+			rows = append(rows, []string{
+				r.AttributeFqn,
+				r.Rule,
+				cli.CommaSeparated(r.ResourceValues),
+				cli.CommaSeparated(r.EntitledValues),
+				decisionOutcome(r.Permitted),
+				r.Explanation,
+			})
+		}
+		t := cli.NewTable().Width(180).Headers("Attribute", "Rule", "Resource Values", "Entitled Values", "Outcome", "Explanation").Rows(rows...)
+		HandleSuccess(cmd, "", t, decision)
+		if outputFormat(cmd).Kind == cli.OutputStyled {
+			fmt.Println(cli.FooterMessage(fmt.Sprintf("Decision: %s %s", decisionOutcome(decision.Permitted), decision.Action)))
+		}
+	},
+}
+
+func decisionOutcome(permitted bool) string {
+	if permitted {
+		return "PERMIT"
+	}
+	return "DENY"
+}
+
+func init() {
+	policyCmd.AddCommand(policy_decideCmd)
+	policy_decideCmd.Flags().StringP("entity", "e", "", "Path to a JSON file of entity claims")
+	policy_decideCmd.Flags().StringSliceVarP(&policy_decideResourceAttrs, "resource-attrs", "r", []string{}, "Attribute value FQNs of the resource")
+	policy_decideCmd.Flags().StringP("action", "a", handlers.SubjectMappingActionDecrypt, "Action to decide, i.e. DECRYPT, TRANSMIT or a custom action")
+}
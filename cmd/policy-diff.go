@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+var policy_diffCmd = &cobra.Command{
+	Use:   cli.ActionDiff,
+	Short: "Show the differences between the policy of two platforms or manifests",
+	Long: `
+Diff - compare the policy graphs of two platforms, two manifests, or a platform and a manifest.
+
+Each side is either a path to a YAML or JSON manifest (as written by 'policy export') or the host:port of a
+platform, whose policy is exported on the fly. Objects are matched by FQN, name, URI or content rather than by
+id, so platforms with independently created policy can be compared, i.e. staging against production.
+
+Added and removed namespaces, attributes, values, subject condition sets, subject mappings, resource mappings,
+KAS registry entries and KAS grants are listed, along with changes to rules, value order, actions, terms, keys,
+active state and labels.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flagHelper := cli.NewFlagHelper(cmd)
+		from := flagHelper.GetRequiredString("from")
+		to := flagHelper.GetRequiredString("to")
+
+		changes := manifest.Diff(loadPolicyGraph(cmd, from), loadPolicyGraph(cmd, to))
+
+		t := cli.NewTable()
+		t.Headers("Kind", "Object", "Change", "Field", "From", "To")
+		for _, c := range changes {
+			t.Row(c.Kind, c.Object, c.Change, c.Field, c.From, c.To)
+		}
+		HandleSuccess(cmd, "", t, changes)
+	},
+}
+
+// loadPolicyGraph reads a manifest when the source is an existing file, and otherwise exports the policy
+// of the platform at the source host
+func loadPolicyGraph(cmd *cobra.Command, source string) *manifest.Manifest {
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		m, err := manifest.Load(source)
+		if err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to load policy manifest (%s)", source), err)
+		}
+		return m
+	}
+
+	h := cli.NewHandlerForHost(cmd, source)
+	defer h.Close()
+
+	m, err := h.ExportManifest()
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to export policy from %s", source), err)
+	}
+	return m
+}
+
+func init() {
+	policyCmd.AddCommand(policy_diffCmd)
+	policy_diffCmd.Flags().String("from", "", "Host (host:port) or manifest file to compare from")
+	policy_diffCmd.Flags().String("to", "", "Host (host:port) or manifest file to compare to")
+}
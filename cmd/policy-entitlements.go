@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policy_entitlementsCmd = &cobra.Command{
+		Use:   "entitlements",
+		Short: "Inspect entitlements [" + policy_entitlementsSimulateCmd.Use + "]",
+		Long: `
+Entitlements - the attribute values and actions an entity is granted by the subject mappings whose subject
+condition sets match its claims.`,
+	}
+
+	policy_entitlementsSimulateCmd = &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate the entitlements of an entity",
+		Long: `
+Evaluates the subject condition set of every subject mapping against a JSON document of entity claims on the
+client, and shows the attribute value FQNs and actions the entity would be entitled to. Use --json to see the
+evaluation of each condition of every subject mapping, including those that did not match.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			flagHelper := cli.NewFlagHelper(cmd)
+			entityFile := flagHelper.GetRequiredString("entity")
+
+			entity, err := handlers.LoadEntity(entityFile)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to read entity from %s", entityFile), err)
+			}
+
+			sim, err := h.SimulateEntitlements(entity)
+			if err != nil {
+				cli.ExitWithError("Failed to simulate entitlements", err)
+			}
+
+			rows := [][]string{}
+			matched := 0
+			for _, e := range sim.Entitlements {
+				rows = append(rows, []string{e.AttributeValueFqn, cli.CommaSeparated(e.Actions), cli.CommaSeparated(e.SubjectMappingIds)})
+				matched += len(e.SubjectMappingIds)
+			}
+			t := cli.NewTable().Headers("Attribute Value FQN", "Actions", "Subject Mappings").Rows(rows...)
+			HandleSuccess(cmd, "", t, sim)
+			if outputFormat(cmd).Kind == cli.OutputStyled {
+				fmt.Println(cli.FooterMessage(fmt.Sprintf("%d of %d subject mappings matched the entity", matched, len(sim.Mappings))))
+			}
+		},
+	}
+)
+
+func init() {
+	policyCmd.AddCommand(policy_entitlementsCmd)
+
+	policy_entitlementsCmd.AddCommand(policy_entitlementsSimulateCmd)
+	policy_entitlementsSimulateCmd.Flags().StringP("entity", "e", "", "Path to a JSON file of entity claims")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+var policy_exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all policy to a portable YAML or JSON manifest",
+	Long: `
+Export - write every namespace, attribute, value, subject condition set, subject mapping, resource mapping,
+KAS registry entry and KAS grant on the platform to a single manifest.
+
+Cross-references are written as FQNs, names and URIs rather than server ids, so the exported manifest can be
+kept as a backup or promoted to another platform with 'policy apply'. Subject condition sets are given a
+stable local name derived from their content.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flagHelper := cli.NewFlagHelper(cmd)
+		format := flagHelper.GetOptionalString("format")
+		file := flagHelper.GetOptionalString("file")
+
+		if format != manifest.FormatYAML && format != manifest.FormatJSON {
+			cli.ExitWithError(fmt.Sprintf("Invalid format '%s'. Must be one of [%s, %s].", format, manifest.FormatYAML, manifest.FormatJSON), nil)
+		}
+
+		h := cli.NewHandler(cmd)
+		defer h.Close()
+
+		m, err := h.ExportManifest()
+		if err != nil {
+			cli.ExitWithError("Failed to export policy", err)
+		}
+
+		var w io.Writer = os.Stdout
+		if file != "" {
+			f, err := os.Create(file)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to create file at path: %s", file), err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if err := manifest.Write(w, m, format); err != nil {
+			cli.ExitWithError("Failed to write policy manifest", err)
+		}
+
+		if file != "" {
+			fmt.Println(cli.SuccessMessage(fmt.Sprintf("Exported policy manifest to %s", file)))
+		}
+	},
+}
+
+func init() {
+	policyCmd.AddCommand(policy_exportCmd)
+	policy_exportCmd.Flags().String("format", manifest.FormatYAML, "Manifest format [yaml, json]")
+	policy_exportCmd.Flags().StringP("file", "f", "", "Path to write the manifest to (default is stdout)")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policy_graphCmd = &cobra.Command{
+		Use:   "graph",
+		Short: "Inspect the references between policy objects [" + policy_graphDependentsCmd.Use + "]",
+	}
+
+	policy_graphDependentsCmd = &cobra.Command{
+		Use:   cli.ActionDependents,
+		Short: "List the objects that depend on a policy object",
+		Long: `
+Dependents - list every object that is stranded when a policy object is deactivated or deleted:
+
+	namespace               its attributes and everything depending on them
+	attribute               its values and KAS grants, and everything depending on the values
+	attribute value         its subject mappings, resource mappings and KAS grants
+	subject condition set   the subject mappings that use it
+	kas registry entry      the KAS grants to attributes and values
+
+The same report is shown when confirming the deactivation or deletion of these objects, and is part of the plan
+printed with --dry-run.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := flagHelper.GetRequiredString("id")
+
+			report, err := h.GetDependents(id)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to find dependents (%s)", id), err)
+			}
+
+			t := cli.NewTable().Headers("Kind", "Id", "Name", "Depends On")
+			for _, d := range report.Dependents {
+				t.Row(d.Kind, d.Id, d.Name, d.DependsOn)
+			}
+			HandleSuccess(cmd, id, t, report)
+			if outputFormat(cmd).Kind == cli.OutputStyled {
+				fmt.Println(cli.FooterMessage(fmt.Sprintf("%d objects depend on %s %s", len(report.Dependents), report.Kind, report.Name)))
+			}
+		},
+	}
+)
+
+// Lists the dependents of the target of a deactivation or deletion, one line each, for the confirmation prompt.
+// When they cannot be found, a warning is printed and the user is still asked to confirm.
+func getDependents(dependents func(string) (*handlers.DependencyReport, error), id string) []string {
+	report, err := dependents(id)
+	if err != nil {
+		fmt.Println(cli.FooterMessage(fmt.Sprintf("Could not find the objects that depend on %s: %v", id, err)))
+		return nil
+	}
+	lines := make([]string, 0, len(report.Dependents))
+	for _, d := range report.Dependents {
+		lines = append(lines, fmt.Sprintf("%s %s (%s)", d.Kind, d.Name, d.Id))
+	}
+	return lines
+}
+
+func init() {
+	policyCmd.AddCommand(policy_graphCmd)
+
+	policy_graphCmd.AddCommand(policy_graphDependentsCmd)
+	policy_graphDependentsCmd.Flags().StringP("id", "i", "", "Id of a namespace, attribute, attribute value, subject condition set or KAS registry entry")
+}
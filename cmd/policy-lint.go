@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+var policy_lintCmd = &cobra.Command{
+	Use:   cli.ActionLint,
+	Short: "Check a policy manifest or the live platform policy for common mistakes",
+	Long: `
+Lint - check policy for mistakes that are valid but likely unintended, before they cause denied access.
+
+Either a manifest (as written by 'policy export') is checked with --manifest, or the policy of the platform is
+exported and checked with --live. Each finding has a severity:
+
+	error     inactive-value-referenced     an inactive value is still referenced by subject or resource mappings
+	error     kas-empty-key                 a registered KAS has neither a local nor a remote public key
+	error     unknown-reference             a mapping or grant references a value, condition set or KAS not in the policy
+	warning   attribute-without-values      an attribute has no values
+	warning   hierarchy-single-value        a HIERARCHY attribute has only one value
+	warning   unused-subject-condition-set  no subject mapping uses the subject condition set
+	warning   duplicate-resource-term       one resource mapping term is mapped to several values
+	info      value-without-kas-grant       no KAS is granted to an active value or its attribute
+
+The command exits with status 1 when a finding is at least as severe as --fail-on, so it can gate CI pipelines.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flagHelper := cli.NewFlagHelper(cmd)
+		file := flagHelper.GetOptionalString("manifest")
+		live := flagHelper.GetOptionalBool("live")
+		failOn := strings.ToLower(flagHelper.GetOptionalString("fail-on"))
+
+		if (file == "") == !live {
+			cli.ExitWithError("Exactly one of --manifest or --live is required", nil)
+		}
+		if !slices.Contains(manifest.Severities, failOn) {
+			cli.ExitWithError(fmt.Sprintf("Invalid --fail-on severity '%s', must be one of %s", failOn, cli.CommaSeparated(manifest.Severities)), nil)
+		}
+
+		var m *manifest.Manifest
+		if live {
+			h := cli.NewHandler(cmd)
+			var err error
+			m, err = h.ExportManifest()
+			// linting needs no connection, and exiting on the findings would skip a deferred close
+			h.Close()
+			if err != nil {
+				cli.ExitWithError("Failed to export policy", err)
+			}
+		} else {
+			// the manifest is not validated, so problems such as empty KAS keys are reported as findings
+			b, err := os.ReadFile(file)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to read policy manifest (%s)", file), err)
+			}
+			if m, err = manifest.Decode(b); err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to load policy manifest (%s)", file), err)
+			}
+		}
+
+		findings := manifest.Lint(m)
+		counts := map[string]int{}
+		failed := manifest.FailsOn(findings, failOn)
+		t := cli.NewTable()
+		t.Headers("Severity", "Rule", "Kind", "Object", "Message")
+		for _, f := range findings {
+			counts[f.Severity]++
+			t.Row(f.Severity, f.Rule, f.Kind, f.Object, f.Message)
+		}
+		HandleSuccess(cmd, "", t, findings)
+
+		if outputFormat(cmd).Kind == cli.OutputStyled {
+			summary := fmt.Sprintf("%d errors, %d warnings, %d info", counts[manifest.SeverityError], counts[manifest.SeverityWarning], counts[manifest.SeverityInfo])
+			if failed {
+				summary += fmt.Sprintf(" - failing on findings of severity %s or higher", failOn)
+			}
+			fmt.Println(cli.FooterMessage(summary))
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	policyCmd.AddCommand(policy_lintCmd)
+	policy_lintCmd.Flags().String("manifest", "", "Policy manifest file (YAML or JSON) to lint")
+	policy_lintCmd.Flags().Bool("live", false, "Lint the policy of the platform")
+	policy_lintCmd.Flags().String("fail-on", manifest.SeverityWarning, "Exit with status 1 on findings of this severity or higher: "+cli.CommaSeparated(manifest.Severities))
+}
@@ -40,7 +40,7 @@ or different attributes tied to each.
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("namespace", h.ResolveNamespaceId, flagHelper.GetRequiredString("id"))
 
 			ns, err := h.GetNamespace(id)
 			if err != nil {
@@ -70,16 +70,15 @@ or different attributes tied to each.
 				cli.ExitWithError("Failed to list namespaces", err)
 			}
 
-			t := cli.NewTable()
-			t.Headers("Id", "Name", "Active")
+			rows := [][]string{}
 			for _, ns := range list {
-				t.Row(
+				rows = append(rows, []string{
 					ns.Id,
 					ns.Name,
 					strconv.FormatBool(ns.Active.GetValue()),
-				)
+				})
 			}
-			HandleSuccess(cmd, "", t, list)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "Name", "Active"}, rows, list)
 		},
 	}
 
@@ -115,7 +114,7 @@ or different attributes tied to each.
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("namespace", h.ResolveNamespaceId, flagHelper.GetRequiredString("id"))
 
 			ns, err := h.GetNamespace(id)
 			if err != nil {
@@ -123,7 +122,9 @@ or different attributes tied to each.
 				cli.ExitWithError(errMsg, err)
 			}
 
-			cli.ConfirmAction(cli.ActionDeactivate, "namespace", ns.Name)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDeactivate, "namespace", ns.Name, getDependents(h.NamespaceDependents, id)...)
+			}
 
 			d, err := h.DeactivateNamespace(id)
 			if err != nil {
@@ -149,7 +150,7 @@ or different attributes tied to each.
 			defer h.Close()
 
 			flagHelper := cli.NewFlagHelper(cmd)
-			id := flagHelper.GetRequiredString("id")
+			id := resolveId("namespace", h.ResolveNamespaceId, flagHelper.GetRequiredString("id"))
 			labels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
 
 			ns, err := h.UpdateNamespace(
@@ -174,7 +175,7 @@ func init() {
 	policyCmd.AddCommand(policy_namespacesCmd)
 
 	policy_namespacesCmd.AddCommand(policy_namespaceGetCmd)
-	policy_namespaceGetCmd.Flags().StringP("id", "i", "", "Id of the namespace")
+	policy_namespaceGetCmd.Flags().StringP("id", "i", "", "Id, name or FQN of the namespace")
 
 	policy_namespacesCmd.AddCommand(policy_namespacesListCmd)
 	policy_namespacesListCmd.Flags().StringP("state", "s", "active", "Filter by state [active, inactive, any]")
@@ -184,9 +185,9 @@ func init() {
 	injectLabelFlags(policy_namespacesCreateCmd, false)
 
 	policy_namespacesCmd.AddCommand(policy_namespaceUpdateCmd)
-	policy_namespaceUpdateCmd.Flags().StringP("id", "i", "", "Id of the namespace")
+	policy_namespaceUpdateCmd.Flags().StringP("id", "i", "", "Id, name or FQN of the namespace")
 	injectLabelFlags(policy_namespaceUpdateCmd, true)
 
 	policy_namespacesCmd.AddCommand(policy_namespaceDeactivateCmd)
-	policy_namespaceDeactivateCmd.Flags().StringP("id", "i", "", "Id of the namespace")
+	policy_namespaceDeactivateCmd.Flags().StringP("id", "i", "", "Id, name or FQN of the namespace")
 }
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/opentdf/otdfctl/docs/man"
@@ -24,6 +26,7 @@ var (
 			policy_resource_mappingsListCmd.Use,
 			policy_resource_mappingsUpdateCmd.Use,
 			policy_resource_mappingsDeleteCmd.Use,
+			policy_resource_mappingsMatchCmd.Use,
 		}),
 		Long: man.PolicyResourceMappings["en"].Long,
 	}
@@ -35,8 +38,32 @@ var (
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			resolveValue := cachedResolver(h.ResolveAttributeValueId)
+			if handled, failed := handleBatchCreate(cmd, func(r cli.BatchRecord) (string, error) {
+				attrId, err := r.RequiredString("attribute-value-id")
+				if err != nil {
+					return "", err
+				}
+				if attrId, err = resolveValue(attrId); err != nil {
+					return "", err
+				}
+				terms := r.Strings("terms")
+				if len(terms) == 0 {
+					return "", errors.New("field 'terms' is required")
+				}
+				metadata, err := parseMetadataLabels(r.Strings("label"))
+				if err != nil {
+					return "", err
+				}
+				resourceMapping, err := h.CreateResourceMapping(attrId, terms, metadata)
+				return resourceMapping.GetId(), err
+			}); handled {
+				exitOnBatchFailure(h, failed)
+				return
+			}
+
 			flagHelper := cli.NewFlagHelper(cmd)
-			attrId := flagHelper.GetRequiredString("attribute-value-id")
+			attrId := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetRequiredString("attribute-value-id"))
 			terms := flagHelper.GetStringSlice("terms", policy_resource_mappingsTerms, cli.FlagHelperStringSliceOptions{
 				Min: 1,
 			})
@@ -94,12 +121,11 @@ var (
 				cli.ExitWithError("Failed to list resource mappings", err)
 			}
 
-			t := cli.NewTable()
-			t.Headers("Id", "Attribute Value Id", "Attribute Value", "Terms")
+			rows := [][]string{}
 			for _, resourceMapping := range rmList {
-				t.Row(resourceMapping.Id, resourceMapping.AttributeValue.Id, resourceMapping.AttributeValue.Value, strings.Join(resourceMapping.Terms, ", "))
+				rows = append(rows, []string{resourceMapping.Id, resourceMapping.AttributeValue.Id, resourceMapping.AttributeValue.Value, strings.Join(resourceMapping.Terms, ", ")})
 			}
-			HandleSuccess(cmd, "", t, rmList)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "Attribute Value Id", "Attribute Value", "Terms"}, rows, rmList)
 		},
 	}
 
@@ -112,7 +138,7 @@ var (
 
 			flagHelper := cli.NewFlagHelper(cmd)
 			id := flagHelper.GetRequiredString("id")
-			attrValueId := flagHelper.GetOptionalString("attribute-value-id")
+			attrValueId := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetOptionalString("attribute-value-id"))
 			terms := flagHelper.GetStringSlice("terms", policy_resource_mappingsTerms, cli.FlagHelperStringSliceOptions{})
 			labels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
 
@@ -141,7 +167,9 @@ var (
 			flagHelper := cli.NewFlagHelper(cmd)
 			id := flagHelper.GetRequiredString("id")
 
-			cli.ConfirmAction(cli.ActionDelete, "resource-mapping", id)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDelete, "resource-mapping", id)
+			}
 
 			resourceMapping, err := h.DeleteResourceMapping(id)
 			if err != nil {
@@ -157,15 +185,59 @@ var (
 			HandleSuccess(cmd, resourceMapping.Id, t, resourceMapping)
 		},
 	}
+
+	policy_resource_mappingsMatchCmd = &cobra.Command{
+		Use:   "match",
+		Short: "Preview the attribute values resource mappings would tag a text with",
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			flagHelper := cli.NewFlagHelper(cmd)
+			text := flagHelper.GetOptionalString("text")
+			file := flagHelper.GetOptionalString("file")
+
+			if (text == "") == (file == "") {
+				cli.ExitWithError("Exactly one of '--text' or '--file' must be provided", nil)
+			}
+			if file != "" {
+				b, err := os.ReadFile(file)
+				if err != nil {
+					cli.ExitWithError(fmt.Sprintf("Failed to read file at path: %s", file), err)
+				}
+				text = string(b)
+			}
+
+			matches, err := h.MatchResourceMappings(text)
+			if err != nil {
+				cli.ExitWithError("Failed to match resource mappings", err)
+			}
+
+			rows := [][]string{}
+			for _, m := range matches.Matches {
+				terms := []string{}
+				for _, t := range m.MatchedTerms {
+					terms = append(terms, fmt.Sprintf("%s (%d)", t.Term, t.Count))
+				}
+				rows = append(rows, []string{m.ResourceMappingId, m.AttributeValueFqn, strings.Join(terms, ", ")})
+			}
+			t := cli.NewTable().Headers("Resource Mapping Id", "Attribute Value FQN", "Matched Terms").Rows(rows...)
+			HandleSuccess(cmd, "", t, matches)
+			if outputFormat(cmd).Kind == cli.OutputStyled {
+				fmt.Println(cli.FooterMessage(fmt.Sprintf("Tagged with %s", cli.CommaSeparated(matches.AttributeValueFqns))))
+			}
+		},
+	}
 )
 
 func init() {
 	policyCmd.AddCommand(policy_resource_mappingsCmd)
 
 	policy_resource_mappingsCmd.AddCommand(policy_resource_mappingsCreateCmd)
-	policy_resource_mappingsCreateCmd.Flags().String("attribute-value-id", "", "Attribute Value ID")
+	policy_resource_mappingsCreateCmd.Flags().String("attribute-value-id", "", "Attribute Value ID or FQN")
 	policy_resource_mappingsCreateCmd.Flags().StringSliceVar(&policy_resource_mappingsTerms, "terms", []string{}, "Synonym terms")
 	injectLabelFlags(policy_resource_mappingsCreateCmd, false)
+	injectBatchFlags(policy_resource_mappingsCreateCmd)
 
 	policy_resource_mappingsCmd.AddCommand(policy_resource_mappingsGetCmd)
 	policy_resource_mappingsGetCmd.Flags().String("id", "", "Resource Mapping ID")
@@ -174,10 +246,14 @@ func init() {
 
 	policy_resource_mappingsCmd.AddCommand(policy_resource_mappingsUpdateCmd)
 	policy_resource_mappingsUpdateCmd.Flags().String("id", "", "Resource Mapping ID")
-	policy_resource_mappingsUpdateCmd.Flags().String("attribute-value-id", "", "Attribute Value ID")
+	policy_resource_mappingsUpdateCmd.Flags().String("attribute-value-id", "", "Attribute Value ID or FQN")
 	policy_resource_mappingsUpdateCmd.Flags().StringSliceVar(&policy_resource_mappingsTerms, "terms", []string{}, "Synonym terms")
 	injectLabelFlags(policy_resource_mappingsUpdateCmd, true)
 
 	policy_resource_mappingsCmd.AddCommand(policy_resource_mappingsDeleteCmd)
 	policy_resource_mappingsDeleteCmd.Flags().String("id", "", "Resource Mapping ID")
+
+	policy_resource_mappingsCmd.AddCommand(policy_resource_mappingsMatchCmd)
+	policy_resource_mappingsMatchCmd.Flags().StringP("text", "t", "", "Text to match the terms of the resource mappings against")
+	policy_resource_mappingsMatchCmd.Flags().StringP("file", "f", "", "Path to a file to match the terms of the resource mappings against")
 }
@@ -8,8 +8,10 @@ import (
 	"strings"
 
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 var (
@@ -19,6 +21,7 @@ var (
 		policy_subject_condition_setListCmd.Use,
 		policy_subject_condition_setUpdateCmd.Use,
 		policy_subject_condition_setDeleteCmd.Use,
+		policy_subject_condition_setTestCmd.Use,
 	}
 
 	policy_subject_condition_setCmd = &cobra.Command{
@@ -69,8 +72,9 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				ssBytes = []byte(ssFlagJSON)
 			}
 
-			if err := json.Unmarshal(ssBytes, &ss); err != nil {
-				cli.ExitWithError("Error unmarshalling subject sets", err)
+			ss, err := parseSubjectSets(string(ssBytes))
+			if err != nil {
+				cli.ExitWithError("Error parsing subject sets", err)
 			}
 
 			scs, err := h.CreateSubjectConditionSet(ss, getMetadataMutable(metadataLabels))
@@ -78,14 +82,9 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				cli.ExitWithError("Error creating subject condition set", err)
 			}
 
-			var subjectSetsJSON []byte
-			if subjectSetsJSON, err = json.Marshal(scs.SubjectSets); err != nil {
-				cli.ExitWithError("Error marshalling subject condition set", err)
-			}
-
 			rows := [][]string{
 				{"Id", scs.Id},
-				{"SubjectSets", string(subjectSetsJSON)},
+				{"SubjectSets", handlers.FormatSubjectSets(scs.SubjectSets)},
 			}
 
 			if mdRows := getMetadataRows(scs.Metadata); mdRows != nil {
@@ -112,14 +111,9 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
 			}
 
-			var subjectSetsJSON []byte
-			if subjectSetsJSON, err = json.Marshal(scs.SubjectSets); err != nil {
-				cli.ExitWithError("Error marshalling subject condition set", err)
-			}
-
 			rows := [][]string{
 				{"Id", scs.Id},
-				{"SubjectSets", string(subjectSetsJSON)},
+				{"SubjectSets", handlers.FormatSubjectSets(scs.SubjectSets)},
 			}
 
 			if mdRows := getMetadataRows(scs.Metadata); mdRows != nil {
@@ -143,18 +137,13 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				cli.ExitWithError("Error listing subject condition sets", err)
 			}
 
-			t := cli.NewTable()
-			t.Headers("Id", "SubjectSets")
+			rows := [][]string{}
 			for _, scs := range scsList {
-				var subjectSetsJSON []byte
-				if subjectSetsJSON, err = json.Marshal(scs.SubjectSets); err != nil {
-					cli.ExitWithError("Error marshalling subject condition set", err)
-				}
-				rowCells := []string{scs.Id, string(subjectSetsJSON)}
-				t.Row(rowCells...)
+				rowCells := []string{scs.Id, handlers.FormatSubjectSets(scs.SubjectSets)}
+				rows = append(rows, rowCells)
 			}
 
-			HandleSuccess(cmd, "", t, scsList)
+			handleListSuccess(cmd, cli.NewTable(), []string{"Id", "SubjectSets"}, rows, scsList)
 		},
 	}
 
@@ -172,8 +161,9 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 
 			var ss []*policy.SubjectSet
 			if ssFlagJSON != "" {
-				if err := json.Unmarshal([]byte(ssFlagJSON), &ss); err != nil {
-					cli.ExitWithError("Error unmarshalling subject sets", err)
+				var err error
+				if ss, err = parseSubjectSets(ssFlagJSON); err != nil {
+					cli.ExitWithError("Error parsing subject sets", err)
 				}
 			}
 
@@ -187,14 +177,9 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				cli.ExitWithError("Error getting subject condition set", err)
 			}
 
-			var subjectSetsJSON []byte
-			if subjectSetsJSON, err = json.Marshal(scs.SubjectSets); err != nil {
-				cli.ExitWithError("Error marshalling subject condition set", err)
-			}
-
 			rows := [][]string{
 				{"Id", scs.Id},
-				{"SubjectSets", string(subjectSetsJSON)},
+				{"SubjectSets", handlers.FormatSubjectSets(scs.SubjectSets)},
 			}
 
 			if mdRows := getMetadataRows(scs.Metadata); mdRows != nil {
@@ -221,20 +206,17 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 				cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
 			}
 
-			cli.ConfirmAction(cli.ActionDelete, "Subject Condition Set", id)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDelete, "Subject Condition Set", id, getDependents(h.SubjectConditionSetDependents, id)...)
+			}
 
 			if err := h.DeleteSubjectConditionSet(id); err != nil {
 				cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
 			}
 
-			var subjectSetsJSON []byte
-			if subjectSetsJSON, err = json.Marshal(scs.SubjectSets); err != nil {
-				cli.ExitWithError("Error marshalling subject condition set", err)
-			}
-
 			rows := [][]string{
 				{"Id", scs.Id},
-				{"SubjectSets", string(subjectSetsJSON)},
+				{"SubjectSets", handlers.FormatSubjectSets(scs.SubjectSets)},
 			}
 
 			if mdRows := getMetadataRows(scs.Metadata); mdRows != nil {
@@ -245,14 +227,80 @@ a Subject Mapping and, by said mapping, an Attribute Value.`,
 			HandleSuccess(cmd, scs.Id, t, scs)
 		},
 	}
+
+	policy_subject_condition_setTestCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Test a subject condition set against the claims of an entity",
+		Long: `
+Evaluates the selectors, operators and condition groups of a subject condition set against a JSON document of
+entity claims, such as the claims of an access token, entirely on the client. Shows the values each selector found
+and which conditions matched.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			h := cli.NewHandler(cmd)
+			defer h.Close()
+
+			flagHelper := cli.NewFlagHelper(cmd)
+			id := flagHelper.GetRequiredString("id")
+			entityFile := flagHelper.GetRequiredString("entity")
+
+			entity, err := handlers.LoadEntity(entityFile)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Failed to read entity from %s", entityFile), err)
+			}
+
+			scs, err := h.GetSubjectConditionSet(id)
+			if err != nil {
+				cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
+			}
+
+			result := handlers.EvaluateSubjectConditionSet(scs, entity)
+			t := cli.NewTable().Headers("Subject Set", "Group", "Condition", "Entity Values", "Matched")
+			t.Rows(getSubjectConditionSetEvaluationRows(result)...)
+			HandleSuccess(cmd, id, t, result)
+			if outputFormat(cmd).Kind == cli.OutputStyled {
+				footer := fmt.Sprintf("Entity matches subject condition set: %t", result.Matched)
+				if result.Explanation != "" {
+					footer += " (" + result.Explanation + ")"
+				}
+				fmt.Println(cli.FooterMessage(footer))
+			}
+		},
+	}
 )
 
+// One row per condition, with the outcome of its group and subject set
+func getSubjectConditionSetEvaluationRows(result handlers.SubjectConditionSetEvaluation) [][]string {
+	rows := [][]string{}
+	for i, ss := range result.SubjectSets {
+		for j, g := range ss.ConditionGroups {
+			group := fmt.Sprintf("%d %s (matched: %t)", j+1, g.BooleanOperator, g.Matched)
+			if g.Error != "" {
+				group = fmt.Sprintf("%d (error: %s)", j+1, g.Error)
+			}
+			for _, c := range g.Conditions {
+				matched := fmt.Sprint(c.Matched)
+				if c.Error != "" {
+					matched = "error: " + c.Error
+				}
+				rows = append(rows, []string{
+					fmt.Sprintf("%d (matched: %t)", i+1, ss.Matched),
+					group,
+					handlers.FormatCondition(c.Selector, c.Operator, c.Values),
+					cli.CommaSeparated(c.EntityValues),
+					matched,
+				})
+			}
+		}
+	}
+	return rows
+}
+
 func init() {
 	policyCmd.AddCommand(policy_subject_condition_setCmd)
 
 	policy_subject_condition_setCmd.AddCommand(policy_subject_condition_setCreateCmd)
 	injectLabelFlags(policy_subject_condition_setCreateCmd, false)
-	policy_subject_condition_setCreateCmd.Flags().StringP("subject-sets", "s", "", "A JSON array of subject sets, containing a list of condition groups, each with one or more conditions")
+	policy_subject_condition_setCreateCmd.Flags().StringP("subject-sets", "s", "", "Subject sets as "+subjectConditionExpressionHelp)
 	policy_subject_condition_setCreateCmd.Flags().StringP("subject-sets-file-json", "j", "", "A JSON file with path from $HOME containing an array of subject sets")
 
 	policy_subject_condition_setCmd.AddCommand(policy_subject_condition_setGetCmd)
@@ -263,40 +311,41 @@ func init() {
 	policy_subject_condition_setCmd.AddCommand(policy_subject_condition_setUpdateCmd)
 	policy_subject_condition_setUpdateCmd.Flags().StringP("id", "i", "", "Id of the subject condition set")
 	injectLabelFlags(policy_subject_condition_setUpdateCmd, true)
-	policy_subject_condition_setUpdateCmd.Flags().StringP("subject-sets", "s", "", "A JSON array of subject sets, containing a list of condition groups, each with one or more conditions")
+	policy_subject_condition_setUpdateCmd.Flags().StringP("subject-sets", "s", "", "Subject sets as "+subjectConditionExpressionHelp)
 
 	policy_subject_condition_setCmd.AddCommand(policy_subject_condition_setDeleteCmd)
 	policy_subject_condition_setDeleteCmd.Flags().StringP("id", "i", "", "Id of the subject condition set")
-}
 
-func getSubjectConditionSetOperatorFromChoice(choice string) (policy.SubjectMappingOperatorEnum, error) {
-	switch choice {
-	case "IN":
-		return policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN, nil
-	case "NOT_IN":
-		return policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN, nil
-	default:
-		return policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_UNSPECIFIED, fmt.Errorf("Unknown operator must be specified ['IN', 'NOT_IN']: %s", choice)
-	}
+	policy_subject_condition_setCmd.AddCommand(policy_subject_condition_setTestCmd)
+	policy_subject_condition_setTestCmd.Flags().StringP("id", "i", "", "Id of the subject condition set")
+	policy_subject_condition_setTestCmd.Flags().StringP("entity", "e", "", "Path to a JSON file of entity claims")
 }
 
-func getSubjectConditionSetBooleanTypeFromChoice(choice string) (policy.ConditionBooleanTypeEnum, error) {
-	switch choice {
-	case "AND":
-		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND, nil
-	case "OR":
-		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR, nil
-	default:
-		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_UNSPECIFIED, fmt.Errorf("Unknown boolean type must be specified ['AND', 'OR']: %s", choice)
+const subjectConditionExpressionHelp = "a condition expression such as '.org.department IN [eng,ops] AND .role NOT_IN [contractor]', " +
+	"with parenthesized condition groups joined by AND and subject sets separated by ';', or a JSON array of subject sets"
+
+// Parses subject sets from either a JSON array or a condition expression
+func parseSubjectSets(input string) ([]*policy.SubjectSet, error) {
+	if strings.HasPrefix(strings.TrimSpace(input), "[") {
+		return getMarshaledSubjectSets(input)
 	}
+	return handlers.ParseSubjectConditionExpression(input)
 }
 
+// Unmarshals a JSON array of subject sets with protojson, which accepts the json output of the CLI as well as
+// the proto field names and enum numbers
 func getMarshaledSubjectSets(subjectSets string) ([]*policy.SubjectSet, error) {
-	var ss []*policy.SubjectSet
-
-	if err := json.Unmarshal([]byte(subjectSets), &ss); err != nil {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(subjectSets), &raw); err != nil {
 		return nil, err
 	}
 
+	ss := make([]*policy.SubjectSet, len(raw))
+	for i, r := range raw {
+		ss[i] = &policy.SubjectSet{}
+		if err := protojson.Unmarshal(r, ss[i]); err != nil {
+			return nil, fmt.Errorf("subject set %d: %w", i+1, err)
+		}
+	}
 	return ss, nil
 }
@@ -1,11 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/subjectmapping"
 	"github.com/spf13/cobra"
@@ -65,22 +66,17 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 			}
 
 			var actionsJSON []byte
-			if actionsJSON, err = json.Marshal(mapping.Actions); err != nil {
+			if actionsJSON, err = cli.MarshalJSON(mapping.Actions); err != nil {
 				cli.ExitWithError("Error marshalling subject mapping actions", err)
 			}
 
-			var subjectSetsJSON []byte
-			if subjectSetsJSON, err = json.Marshal(mapping.SubjectConditionSet.SubjectSets); err != nil {
-				cli.ExitWithError("Error marshalling subject condition set", err)
-			}
-
 			rows := [][]string{
 				{"Id", mapping.Id},
 				{"Subject AttrVal: Id", mapping.AttributeValue.Id},
 				{"Subject AttrVal: Value", mapping.AttributeValue.Value},
 				{"Actions", string(actionsJSON)},
 				{"Subject Condition Set: Id", mapping.SubjectConditionSet.Id},
-				{"Subject Condition Set", string(subjectSetsJSON)},
+				{"Subject Condition Set", handlers.FormatSubjectSets(mapping.SubjectConditionSet.GetSubjectSets())},
 			}
 
 			if mdRows := getMetadataRows(mapping.Metadata); mdRows != nil {
@@ -104,30 +100,25 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 				cli.ExitWithError("Failed to get subject mappings", err)
 			}
 
-			t := cli.NewTable().Width(180)
-			t.Headers("Id", "Subject AttrVal: Id", "Subject AttrVal: Value", "Actions", "Subject Condition Set: Id", "Subject Condition Set")
+			rows := [][]string{}
 			for _, sm := range list {
 				var actionsJSON []byte
-				if actionsJSON, err = json.Marshal(sm.Actions); err != nil {
+				if actionsJSON, err = cli.MarshalJSON(sm.Actions); err != nil {
 					cli.ExitWithError("Error marshalling subject mapping actions", err)
 				}
 
-				var subjectSetsJSON []byte
-				if subjectSetsJSON, err = json.Marshal(sm.SubjectConditionSet.SubjectSets); err != nil {
-					cli.ExitWithError("Error marshalling subject condition set", err)
-				}
-
 				rowCells := []string{
 					sm.Id,
 					sm.AttributeValue.Id,
 					sm.AttributeValue.Value,
 					string(actionsJSON),
 					sm.SubjectConditionSet.Id,
-					string(subjectSetsJSON),
+					handlers.FormatSubjectSets(sm.SubjectConditionSet.GetSubjectSets()),
 				}
-				t.Row(rowCells...)
+				rows = append(rows, rowCells)
 			}
-			HandleSuccess(cmd, "", t, list)
+			headers := []string{"Id", "Subject AttrVal: Id", "Subject AttrVal: Value", "Actions", "Subject Condition Set: Id", "Subject Condition Set"}
+			handleListSuccess(cmd, cli.NewTable().Width(180), headers, rows, list)
 		},
 	}
 
@@ -138,8 +129,40 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 			h := cli.NewHandler(cmd)
 			defer h.Close()
 
+			resolveValue := cachedResolver(h.ResolveAttributeValueId)
+			if handled, failed := handleBatchCreate(cmd, func(r cli.BatchRecord) (string, error) {
+				attrValueId, err := r.RequiredString("attribute-value-id")
+				if err != nil {
+					return "", err
+				}
+				if attrValueId, err = resolveValue(attrValueId); err != nil {
+					return "", err
+				}
+				actions, err := getSubjectMappingActions(r.Strings("action-standard"), r.Strings("action-custom"))
+				if err != nil {
+					return "", err
+				}
+				var scs *subjectmapping.SubjectConditionSetCreate
+				if newScsJSON := r.String("subject-condition-set-new"); newScsJSON != "" {
+					ss, err := parseSubjectSets(newScsJSON)
+					if err != nil {
+						return "", fmt.Errorf("invalid subject-condition-set-new: %w", err)
+					}
+					scs = &subjectmapping.SubjectConditionSetCreate{SubjectSets: ss}
+				}
+				metadata, err := parseMetadataLabels(r.Strings("label"))
+				if err != nil {
+					return "", err
+				}
+				mapping, err := h.CreateNewSubjectMapping(attrValueId, actions, r.String("subject-condition-set-id"), scs, metadata)
+				return mapping.GetId(), err
+			}); handled {
+				exitOnBatchFailure(h, failed)
+				return
+			}
+
 			flagHelper := cli.NewFlagHelper(cmd)
-			attrValueId := flagHelper.GetRequiredString("attribute-value-id")
+			attrValueId := resolveId("attribute value", h.ResolveAttributeValueId, flagHelper.GetRequiredString("attribute-value-id"))
 			standardActions := flagHelper.GetStringSlice("action-standard", standardActions, cli.FlagHelperStringSliceOptions{Min: 0})
 			customActions := flagHelper.GetStringSlice("action-custom", customActions, cli.FlagHelperStringSliceOptions{Min: 0})
 			metadataLabels := flagHelper.GetStringSlice("label", metadataLabels, cli.FlagHelperStringSliceOptions{Min: 0})
@@ -147,26 +170,16 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 			// NOTE: labels within a new Subject Condition Set created on a SM creation are not supported
 			newScsJSON := flagHelper.GetOptionalString("subject-condition-set-new")
 
-			// validations
-			if len(standardActions) == 0 && len(customActions) == 0 {
-				cli.ExitWithError("At least one Standard or Custom Action [--action-standard, --action-custom] is required", nil)
-			}
-			if len(standardActions) > 0 {
-				for _, a := range standardActions {
-					a = strings.ToUpper(a)
-					if a != "DECRYPT" && a != "TRANSMIT" {
-						cli.ExitWithError(fmt.Sprintf("Invalid Standard Action: '%s'. Must be one of [DECRYPT, TRANSMIT].", a), nil)
-					}
-				}
+			actions, err := getSubjectMappingActions(standardActions, customActions)
+			if err != nil {
+				cli.ExitWithError(err.Error(), nil)
 			}
 
-			actions := getFullActionsList(standardActions, customActions)
-
 			var ss []*policy.SubjectSet
 			var scs *subjectmapping.SubjectConditionSetCreate
 			if newScsJSON != "" {
-				if err := json.Unmarshal([]byte(newScsJSON), &ss); err != nil {
-					cli.ExitWithError("Error unmarshalling subject sets", err)
+				if ss, err = parseSubjectSets(newScsJSON); err != nil {
+					cli.ExitWithError("Error parsing subject sets", err)
 				}
 				scs = &subjectmapping.SubjectConditionSetCreate{
 					SubjectSets: ss,
@@ -179,23 +192,16 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 			}
 
 			var actionsJSON []byte
-			if actionsJSON, err = json.Marshal(mapping.Actions); err != nil {
+			if actionsJSON, err = cli.MarshalJSON(mapping.Actions); err != nil {
 				cli.ExitWithError("Error marshalling subject mapping actions", err)
 			}
 
-			var subjectSetsJSON []byte
-			if mapping.SubjectConditionSet != nil {
-				if subjectSetsJSON, err = json.Marshal(mapping.SubjectConditionSet.SubjectSets); err != nil {
-					cli.ExitWithError("Error marshalling subject condition set", err)
-				}
-			}
-
 			rows := [][]string{
 				{"Id", mapping.Id},
 				{"Subject AttrVal: Id", mapping.AttributeValue.Id},
 				{"Actions", string(actionsJSON)},
 				{"Subject Condition Set: Id", mapping.SubjectConditionSet.Id},
-				{"Subject Condition Set", string(subjectSetsJSON)},
+				{"Subject Condition Set", handlers.FormatSubjectSets(mapping.SubjectConditionSet.GetSubjectSets())},
 				{"Attribute Value Id", mapping.AttributeValue.Id},
 			}
 
@@ -224,7 +230,9 @@ Note: SubjectConditionSets are reusable among SubjectMappings and are available
 				cli.ExitWithError(errMsg, err)
 			}
 
-			cli.ConfirmAction(cli.ActionDelete, "subject mapping", sm.Id)
+			if !h.DryRun {
+				cli.ConfirmAction(cli.ActionDelete, "subject mapping", sm.Id)
+			}
 
 			deleted, err := h.DeleteSubjectMapping(id)
 			if err != nil {
@@ -261,7 +269,7 @@ full set of actions on update. `,
 					}
 				}
 			}
-			actions := getFullActionsList(standardActions, customActions)
+			actions := handlers.GetFullActionsList(standardActions, customActions)
 
 			updated, err := h.UpdateSubjectMapping(
 				id,
@@ -279,34 +287,18 @@ full set of actions on update. `,
 	}
 )
 
-func getSubjectMappingMappingActionEnumFromChoice(readable string) policy.Action_StandardAction {
-	switch readable {
-	case "DECRYPT":
-		return policy.Action_STANDARD_ACTION_DECRYPT
-	case "TRANSMIT":
-		return policy.Action_STANDARD_ACTION_TRANSMIT
-	default:
-		return policy.Action_STANDARD_ACTION_UNSPECIFIED
+// Validates the standard actions and combines them with the custom ones
+func getSubjectMappingActions(standardActions []string, customActions []string) ([]*policy.Action, error) {
+	if len(standardActions) == 0 && len(customActions) == 0 {
+		return nil, errors.New("At least one Standard or Custom Action [--action-standard, --action-custom] is required")
 	}
-}
-
-func getFullActionsList(standardActions, customActions []string) []*policy.Action {
-	actions := []*policy.Action{}
 	for _, a := range standardActions {
-		actions = append(actions, &policy.Action{
-			Value: &policy.Action_Standard{
-				Standard: getSubjectMappingMappingActionEnumFromChoice(a),
-			},
-		})
-	}
-	for _, a := range customActions {
-		actions = append(actions, &policy.Action{
-			Value: &policy.Action_Custom{
-				Custom: a,
-			},
-		})
+		a = strings.ToUpper(a)
+		if a != "DECRYPT" && a != "TRANSMIT" {
+			return nil, fmt.Errorf("Invalid Standard Action: '%s'. Must be one of [DECRYPT, TRANSMIT].", a)
+		}
 	}
-	return actions
+	return handlers.GetFullActionsList(standardActions, customActions), nil
 }
 
 func init() {
@@ -318,12 +310,13 @@ func init() {
 	policy_subject_mappingsCmd.AddCommand(policy_subject_mappingsListCmd)
 
 	policy_subject_mappingsCmd.AddCommand(policy_subject_mappingCreateCmd)
-	policy_subject_mappingCreateCmd.Flags().StringP("attribute-value-id", "a", "", "Id of the mapped Attribute Value")
+	policy_subject_mappingCreateCmd.Flags().StringP("attribute-value-id", "a", "", "Id or FQN of the mapped Attribute Value")
 	policy_subject_mappingCreateCmd.Flags().StringSliceVarP(&standardActions, "action-standard", "s", []string{}, "Standard Action: [DECRYPT, TRANSMIT]")
 	policy_subject_mappingCreateCmd.Flags().StringSliceVarP(&customActions, "action-custom", "c", []string{}, "Custom Action")
 	policy_subject_mappingCreateCmd.Flags().String("subject-condition-set-id", "", "Known pre-existing Subject Condition Set Id")
-	policy_subject_mappingCreateCmd.Flags().String("subject-condition-set-new", "", "JSON array of Subject Sets to create a new Subject Condition Set associated with the created Subject Mapping")
+	policy_subject_mappingCreateCmd.Flags().String("subject-condition-set-new", "", "Subject Sets of a new Subject Condition Set associated with the created Subject Mapping, as "+subjectConditionExpressionHelp)
 	injectLabelFlags(policy_subject_mappingCreateCmd, false)
+	injectBatchFlags(policy_subject_mappingCreateCmd)
 
 	policy_subject_mappingsCmd.AddCommand(policy_subject_mappingUpdateCmd)
 	policy_subject_mappingUpdateCmd.Flags().StringP("id", "i", "", "Id of the subject mapping")
@@ -8,12 +8,17 @@ import (
 	"os"
 
 	"github.com/opentdf/otdfctl/internal/config"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/credstore"
+	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/spf13/cobra"
 )
 
 var (
 	cfgFile    string
 	OtdfctlCfg config.Config
+	// the profile selected with --profile or the config's current profile, if any
+	activeProfile *config.Profile
 
 	configFlagOverrides = config.ConfigFlagOverrides{}
 )
@@ -25,6 +30,82 @@ var rootCmd = &cobra.Command{
 	Long: `
 A command line tool to manage Virtru Data Security Platform.
 `,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyProfile(cmd)
+		useCredentialStore()
+	},
+}
+
+// useCredentialStore selects where logins are cached, as configured under 'credentials'. The encrypted file store
+// takes its passphrase from OTDFCTL_CREDENTIALS_PASSPHRASE, and the memory store its login from the environment.
+// The store is only opened once a credential is read or written, so a missing passphrase fails just the commands
+// that need it.
+func useCredentialStore() {
+	handlers.UseCredentialStore(credstore.NewLazy(credstore.Config{
+		Backend:    OtdfctlCfg.Credentials.Store,
+		File:       OtdfctlCfg.Credentials.File,
+		Passphrase: os.Getenv("OTDFCTL_CREDENTIALS_PASSPHRASE"),
+		Defaults:   handlers.CredentialsFromEnv(),
+	}))
+}
+
+// applyProfile fills in the connection settings of the active profile (from --profile or the config's current
+// profile) wherever they were not given explicitly as flags
+func applyProfile(cmd *cobra.Command) {
+	p, ok, err := OtdfctlCfg.ActiveProfile(cmd.Flag("profile").Value.String())
+	if err != nil {
+		cli.ExitWithError("Failed to load profile", err)
+	}
+	if !ok {
+		return
+	}
+
+	fill := []struct{ flag, value string }{
+		{"host", p.Host},
+		{"plaintext", boolFlagValue(p.TLS.Plaintext)},
+		{"tls-ca-file", p.TLS.CAFile},
+		{"tls-cert-file", p.TLS.CertFile},
+		{"tls-key-file", p.TLS.KeyFile},
+		{"tls-server-name", p.TLS.ServerName},
+		{"insecure-skip-verify", boolFlagValue(p.TLS.InsecureSkipVerify)},
+	}
+	for _, f := range fill {
+		if f.value == "" || cmd.Flags().Changed(f.flag) {
+			continue
+		}
+		// set the value directly so the flag is not marked as changed by the user
+		if err := cmd.Flag(f.flag).Value.Set(f.value); err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to apply profile setting (%s)", f.flag), err)
+		}
+	}
+	activeProfile = &p
+}
+
+func boolFlagValue(b bool) string {
+	if b {
+		return "true"
+	}
+	return ""
+}
+
+// outputFormat is the format given with --output (or --json), else the active profile's or the configured one
+func outputFormat(cmd *cobra.Command) cli.OutputFormat {
+	format := OtdfctlCfg.Output.Format
+	if activeProfile != nil && activeProfile.Output.Format != "" {
+		format = activeProfile.Output.Format
+	}
+	if configFlagOverrides.OutputFormatJSON {
+		format = cli.OutputJSON
+	}
+	if f := cmd.Flag("output"); f != nil && f.Value.String() != "" {
+		format = f.Value.String()
+	}
+
+	f, err := cli.ParseOutputFormat(format)
+	if err != nil {
+		cli.ExitWithError("Invalid output format", err)
+	}
+	return f
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -37,7 +118,22 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&configFlagOverrides.OutputFormatJSON, "json", false, "output single command in JSON (overrides configured output format)")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "output format of a single command: "+cli.OutputFormatHelp+" (overrides configured output format)")
 	rootCmd.PersistentFlags().String("host", "localhost:8080", "host:port of the Virtru Data Security Platform gRPC server")
+	rootCmd.PersistentFlags().Bool("plaintext", false, "connect to the platform without TLS, i.e. to a local development platform")
+	rootCmd.PersistentFlags().String("tls-ca-file", "", "PEM bundle of the CAs trusted to sign the platform's certificate (default is the system roots)")
+	rootCmd.PersistentFlags().String("tls-cert-file", "", "PEM client certificate for mTLS")
+	rootCmd.PersistentFlags().String("tls-key-file", "", "PEM client key for mTLS")
+	rootCmd.PersistentFlags().String("tls-server-name", "", "server name used to verify the platform's certificate (default is the host)")
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false, "skip verification of the platform's certificate (local development only)")
+	rootCmd.PersistentFlags().String("act-as", "", "act as this subject, i.e. a service account, by exchanging the token of the cached login (RFC 8693 token exchange)")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "print the request a policy create, update, deactivate or delete would send and the current server state, without sending it")
+	rootCmd.PersistentFlags().Int("limit", 0, "maximum number of items a list command shows (default is all). List commands fetch the full list from the platform and filter, sort and page it on the client.")
+	rootCmd.PersistentFlags().Int("offset", 0, "number of items a list command skips, after fetching the full list")
+	rootCmd.PersistentFlags().String("page-token", "", "token of the page a list command shows, as printed below the previous page")
+	rootCmd.PersistentFlags().String("sort-by", "", "column a list command sorts by, descending with a '-' prefix (i.e. '-name')")
+	rootCmd.PersistentFlags().String("filter", "", "filter of a list command, as column=value, column!=value or column~=value (contains) terms separated by commas, i.e. 'namespace=example.com,active=true'")
+	rootCmd.PersistentFlags().String("profile", "", "name of the connection profile to use (default is the current profile in the config file)")
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config-file", "", "config file (default is $HOME/.otdfctl.yaml)")
 
 	cfg, err := config.LoadConfig("otdfctl")
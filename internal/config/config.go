@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -9,14 +10,49 @@ import (
 
 	"github.com/creasty/defaults"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 type Output struct {
-	Format string `yaml:"format" default:"styled"`
+	Format string `yaml:"format" json:"format,omitempty" default:"styled"`
+}
+
+// TLS settings for the connection to the platform
+type TLS struct {
+	// connect without TLS, i.e. to a local development platform
+	Plaintext          bool   `yaml:"plaintext,omitempty" json:"plaintext,omitempty"`
+	CAFile             string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	CertFile           string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile            string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+	ServerName         string `yaml:"serverName,omitempty" json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty" json:"insecureSkipVerify,omitempty"`
+}
+
+// Profile is a named set of connection settings for one platform
+type Profile struct {
+	Host string `yaml:"host" json:"host"`
+	TLS  TLS    `yaml:"tls,omitempty" json:"tls"`
+	// the IdP to log in with, when not the one the platform advertises
+	Issuer        string `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+	TokenEndpoint string `yaml:"tokenEndpoint,omitempty" json:"tokenEndpoint,omitempty"`
+	ClientId      string `yaml:"clientId,omitempty" json:"clientId,omitempty"`
+	// overrides the top-level output format while the profile is in use
+	Output Output `yaml:"output,omitempty" json:"output"`
+}
+
+// Credentials selects where logins are cached
+type Credentials struct {
+	// keyring (default), file or memory
+	Store string `yaml:"store,omitempty"`
+	// path of the encrypted file store (default is $HOME/.otdfctl/credentials.enc)
+	File string `yaml:"file,omitempty"`
 }
 
 type Config struct {
-	Output Output `yaml:"output"`
+	Output         Output             `yaml:"output"`
+	Credentials    Credentials        `yaml:"credentials,omitempty"`
+	CurrentProfile string             `yaml:"currentProfile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles,omitempty"`
 }
 
 // captures all CLI flags that will override pre-specified config values
@@ -34,7 +70,9 @@ const (
 	OutputJSON   = "json"
 	OutputStyled = "styled"
 
-	ErrLoadingConfig Error = "error loading config"
+	ErrLoadingConfig   Error = "error loading config"
+	ErrSavingConfig    Error = "error saving config"
+	ErrProfileNotFound Error = "profile not found"
 )
 
 // Load config with viper.
@@ -60,6 +98,10 @@ func LoadConfig(key string) (*Config, error) {
 	viper.SetEnvPrefix(key)
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	// viper only reads the environment for keys it knows of, so the credential store can be chosen with
+	// OTDFCTL_CREDENTIALS_STORE on machines whose config file does not mention it
+	viper.SetDefault("credentials.store", "")
+	viper.SetDefault("credentials.file", "")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, errors.Join(err, ErrLoadingConfig)
@@ -77,13 +119,157 @@ func LoadConfig(key string) (*Config, error) {
 	return config, nil
 }
 
-func UpdateOutputFormat(format string) {
-	v := viper.GetViper()
-	format = strings.ToLower(format)
-	if format == OutputJSON {
-		v.Set("output.format", OutputJSON)
-	} else {
-		v.Set("output.format", OutputStyled)
+// UpdateOutputFormat saves the output format, which the caller has validated
+func UpdateOutputFormat(format string) error {
+	viper.Set("output.format", format)
+	return updateConfigFile(viper.ConfigFileUsed(), keyUpdate{path: []string{"output", "format"}, value: format})
+}
+
+// Profile returns the named profile. Profile names are case-insensitive.
+func (c *Config) Profile(name string) (Profile, error) {
+	p, ok := c.Profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return p, nil
+}
+
+// ActiveProfile returns the profile selected by name, falling back to the current profile. When neither is
+// set, ok is false and the connection settings come from flags alone.
+func (c *Config) ActiveProfile(name string) (p Profile, ok bool, err error) {
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return Profile{}, false, nil
+	}
+	p, err = c.Profile(name)
+	return p, err == nil, err
+}
+
+// Adds or replaces a profile and saves the config file
+func (c *Config) AddProfile(name string, p Profile) error {
+	if name == "" {
+		return errors.New("profile name must not be empty")
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	name = strings.ToLower(name)
+	c.Profiles[name] = p
+	return updateConfigFile(viper.ConfigFileUsed(), keyUpdate{path: []string{"profiles", name}, value: p})
+}
+
+// Removes a profile, clearing the current profile if it was in use, and saves the config file
+func (c *Config) RemoveProfile(name string) error {
+	name = strings.ToLower(name)
+	if _, err := c.Profile(name); err != nil {
+		return err
+	}
+	delete(c.Profiles, name)
+	updates := []keyUpdate{{path: []string{"profiles", name}}}
+	if c.CurrentProfile == name {
+		c.CurrentProfile = ""
+		updates = append(updates, keyUpdate{path: []string{"currentProfile"}})
+	}
+	return updateConfigFile(viper.ConfigFileUsed(), updates...)
+}
+
+// Sets the profile used when no --profile flag is given and saves the config file
+func (c *Config) UseProfile(name string) error {
+	name = strings.ToLower(name)
+	if _, err := c.Profile(name); err != nil {
+		return err
+	}
+	c.CurrentProfile = name
+	return updateConfigFile(viper.ConfigFileUsed(), keyUpdate{path: []string{"currentProfile"}, value: name})
+}
+
+// keyUpdate sets the key at the path of nested mapping keys to the value, or removes it when the value is nil
+type keyUpdate struct {
+	path  []string
+	value interface{}
+}
+
+// Edits only the updated keys of the config file, leaving comments and keys the Config does not know of in
+// place. The loaded Config is not written back, as it also holds defaults and environment overrides.
+func updateConfigFile(file string, updates ...keyUpdate) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Join(err, ErrSavingConfig)
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return errors.Join(err, ErrSavingConfig)
+	}
+	if len(doc.Content) == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return errors.Join(errors.New("config file is not a mapping"), ErrSavingConfig)
+	}
+
+	for _, u := range updates {
+		if err := updateKey(root, u.path, u.value); err != nil {
+			return errors.Join(fmt.Errorf("%s: %w", strings.Join(u.path, "."), err), ErrSavingConfig)
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return errors.Join(err, ErrSavingConfig)
+	}
+	if err := enc.Close(); err != nil {
+		return errors.Join(err, ErrSavingConfig)
+	}
+	if err := os.WriteFile(file, out.Bytes(), 0o600); err != nil {
+		return errors.Join(err, ErrSavingConfig)
+	}
+	return nil
+}
+
+func updateKey(mapping *yaml.Node, path []string, value interface{}) error {
+	if mapping.Kind != yaml.MappingNode {
+		return errors.New("not a mapping")
+	}
+	// mapping content alternates keys and values, and keys are case-insensitive as viper reads them
+	i := 0
+	for ; i < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, path[0]) {
+			break
+		}
+	}
+	found := i < len(mapping.Content)
+
+	if len(path) > 1 {
+		if !found {
+			if value == nil {
+				return nil
+			}
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}, &yaml.Node{Kind: yaml.MappingNode})
+		}
+		return updateKey(mapping.Content[i+1], path[1:], value)
+	}
+
+	if value == nil {
+		if found {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		}
+		return nil
+	}
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+	if found {
+		// keep the comments of the replaced value
+		node.HeadComment, node.LineComment, node.FootComment = mapping.Content[i+1].HeadComment, mapping.Content[i+1].LineComment, mapping.Content[i+1].FootComment
+		mapping.Content[i+1] = node
+		return nil
 	}
-	viper.WriteConfig()
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}, node)
+	return nil
 }
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateConfigFile(t *testing.T) {
+	const file = `# otdfctl settings
+output:
+  format: styled # the default
+unknownKey: kept
+currentProfile: prod
+profiles:
+  Prod:
+    host: https://prod.example.com
+  dev:
+    host: http://localhost:8080
+`
+	tests := []struct {
+		name    string
+		updates []keyUpdate
+		want    string
+	}{
+		{
+			name:    "replaces a value keeping its comment",
+			updates: []keyUpdate{{path: []string{"output", "format"}, value: "json"}},
+			want: `# otdfctl settings
+output:
+  format: json # the default
+unknownKey: kept
+currentProfile: prod
+profiles:
+  Prod:
+    host: https://prod.example.com
+  dev:
+    host: http://localhost:8080
+`,
+		},
+		{
+			name: "removes keys matching in any case",
+			updates: []keyUpdate{
+				{path: []string{"profiles", "prod"}},
+				{path: []string{"currentProfile"}},
+				{path: []string{"missing", "key"}},
+			},
+			want: `# otdfctl settings
+output:
+  format: styled # the default
+unknownKey: kept
+profiles:
+  dev:
+    host: http://localhost:8080
+`,
+		},
+		{
+			name: "adds nested keys",
+			updates: []keyUpdate{
+				{path: []string{"profiles", "staging"}, value: Profile{Host: "https://staging.example.com", TLS: TLS{Plaintext: true}}},
+				{path: []string{"credentials", "store"}, value: "file"},
+			},
+			want: `# otdfctl settings
+output:
+  format: styled # the default
+unknownKey: kept
+currentProfile: prod
+profiles:
+  Prod:
+    host: https://prod.example.com
+  dev:
+    host: http://localhost:8080
+  staging:
+    host: https://staging.example.com
+    tls:
+      plaintext: true
+credentials:
+  store: file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "otdfctl.yaml")
+			if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := updateConfigFile(path, tt.updates...); err != nil {
+				t.Fatal(err)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("expected\n%s\ngot\n%s", tt.want, b)
+			}
+		})
+	}
+}
+
+func TestUpdateEmptyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otdfctl.yaml")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateConfigFile(path, keyUpdate{path: []string{"currentProfile"}, value: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "currentProfile: dev\n" {
+		t.Errorf("expected only the updated key, got %q", b)
+	}
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+)
+
+const (
+	BatchSucceeded = "succeeded"
+	BatchFailed    = "failed"
+	BatchSkipped   = "skipped"
+	// the mutation of the row was only planned, as with --dry-run
+	BatchPlanned = "planned"
+)
+
+// BatchRecord is a row of a --from-file CSV file or a line of an NDJSON file, with the fields named like the flags
+// of the command
+type BatchRecord struct {
+	// line of the record in the file, counting the CSV header
+	Line   int
+	Fields map[string]interface{}
+}
+
+// BatchResult is the outcome of creating one record
+type BatchResult struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Id     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadBatchFile reads the records of a CSV file with a header row, or of an NDJSON file with one object per line.
+// The format follows the file extension (.csv, .ndjson or .jsonl), '-' reads NDJSON from stdin.
+func ReadBatchFile(path string) ([]BatchRecord, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readBatchCSV(b)
+	case ".ndjson", ".jsonl", "":
+		return readBatchNDJSON(b)
+	}
+	return nil, fmt.Errorf("unsupported file '%s', must be a .csv, .ndjson or .jsonl file", path)
+}
+
+func readBatchCSV(b []byte) ([]BatchRecord, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("CSV file has no header row")
+	}
+	headers := rows[0]
+	records := make([]BatchRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		rec := BatchRecord{Line: i + 2, Fields: map[string]interface{}{}}
+		for j, h := range headers {
+			if row[j] != "" {
+				rec.Fields[strings.TrimSpace(h)] = row[j]
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func readBatchNDJSON(b []byte) ([]BatchRecord, error) {
+	var records []BatchRecord
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" {
+			continue
+		}
+		rec := BatchRecord{Line: line}
+		if err := json.Unmarshal([]byte(text), &rec.Fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records, s.Err()
+}
+
+// String returns a field as text, writing objects and arrays of NDJSON records as JSON
+func (r BatchRecord) String(name string) string {
+	switch v := r.Fields[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// RequiredString returns a field as text, failing when it is missing or empty
+func (r BatchRecord) RequiredString(name string) (string, error) {
+	v := r.String(name)
+	if v == "" {
+		return "", fmt.Errorf("field '%s' is required", name)
+	}
+	return v, nil
+}
+
+// Strings returns a list field, which is an array in NDJSON records and comma separated in CSV, the same as the
+// values of a repeatable flag
+func (r BatchRecord) Strings(name string) []string {
+	var list []string
+	switch v := r.Fields[name].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+				list = append(list, s)
+			}
+		}
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if s := strings.TrimSpace(item); s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+// RunBatch creates the records with at most concurrency calls at a time. Without continueOnError no further records
+// are started after the first failure, and those are reported as skipped. Results are in the order of the records.
+func RunBatch(records []BatchRecord, concurrency int, continueOnError bool, create func(BatchRecord) (string, error)) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]BatchResult, len(records))
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	sem := make(chan struct{}, concurrency)
+	for i, rec := range records {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed && !continueOnError
+		mu.Unlock()
+		if stop {
+			<-sem
+			results[i] = BatchResult{Line: rec.Line, Status: BatchSkipped}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, rec BatchRecord) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			id, err := create(rec)
+			result := BatchResult{Line: rec.Line, Status: BatchSucceeded, Id: id}
+			var plan *handlers.DryRunError
+			switch {
+			case errors.As(err, &plan):
+				result.Status = BatchPlanned
+			case err != nil:
+				result.Status = BatchFailed
+				result.Error = err.Error()
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+			results[i] = result
+		}(i, rec)
+	}
+	wg.Wait()
+	return results
+}
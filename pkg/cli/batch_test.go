@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+)
+
+func TestReadBatchFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		want     []BatchRecord
+		err      bool
+	}{
+		{
+			name:     "csv with header, leaving out empty fields",
+			file:     "values.csv",
+			contents: "attribute, value ,label\nhttps://example.com/attr/a,one,\"team=a,env=dev\"\nhttps://example.com/attr/a,two,\n",
+			want: []BatchRecord{
+				{Line: 2, Fields: map[string]interface{}{"attribute": "https://example.com/attr/a", "value": "one", "label": "team=a,env=dev"}},
+				{Line: 3, Fields: map[string]interface{}{"attribute": "https://example.com/attr/a", "value": "two"}},
+			},
+		},
+		{
+			name:     "csv with a header only",
+			file:     "values.csv",
+			contents: "attribute,value\n",
+			want:     []BatchRecord{},
+		},
+		{
+			name:     "empty csv",
+			file:     "values.csv",
+			contents: "",
+			err:      true,
+		},
+		{
+			name:     "csv row with too many fields",
+			file:     "values.csv",
+			contents: "attribute,value\na,b,c\n",
+			err:      true,
+		},
+		{
+			name:     "ndjson skipping blank lines",
+			file:     "values.ndjson",
+			contents: "{\"value\":\"one\",\"label\":[\"team=a\",\"env=dev\"]}\n\n{\"value\":\"two\"}\n",
+			want: []BatchRecord{
+				{Line: 1, Fields: map[string]interface{}{"value": "one", "label": []interface{}{"team=a", "env=dev"}}},
+				{Line: 3, Fields: map[string]interface{}{"value": "two"}},
+			},
+		},
+		{
+			name:     "jsonl",
+			file:     "values.jsonl",
+			contents: "{\"value\":\"one\"}",
+			want:     []BatchRecord{{Line: 1, Fields: map[string]interface{}{"value": "one"}}},
+		},
+		{
+			name:     "ndjson with an invalid line",
+			file:     "values.ndjson",
+			contents: "{\"value\":\"one\"}\nnot json\n",
+			err:      true,
+		},
+		{
+			name:     "unsupported extension",
+			file:     "values.yaml",
+			contents: "value: one\n",
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadBatchFile(path)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBatchRecordFields(t *testing.T) {
+	csv := BatchRecord{Fields: map[string]interface{}{"label": " team=a, env=dev ,", "name": " a "}}
+	ndjson := BatchRecord{Fields: map[string]interface{}{"label": []interface{}{"team=a", " env=dev"}, "metadata": map[string]interface{}{"a": "b"}}}
+
+	if got := csv.Strings("label"); !reflect.DeepEqual(got, []string{"team=a", "env=dev"}) {
+		t.Errorf("expected the comma separated list, got %v", got)
+	}
+	if got := ndjson.Strings("label"); !reflect.DeepEqual(got, []string{"team=a", "env=dev"}) {
+		t.Errorf("expected the array, got %v", got)
+	}
+	if got := csv.String("name"); got != "a" {
+		t.Errorf("expected the trimmed field, got %q", got)
+	}
+	if got := ndjson.String("metadata"); got != `{"a":"b"}` {
+		t.Errorf("expected an object as JSON, got %q", got)
+	}
+	if _, err := csv.RequiredString("missing"); err == nil {
+		t.Error("expected an error for a missing required field")
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	errCreate := errors.New("create failed")
+	records := make([]BatchRecord, 5)
+	for i := range records {
+		records[i] = BatchRecord{Line: i + 2, Fields: map[string]interface{}{"name": fmt.Sprint(i)}}
+	}
+	succeeded := func(line int) BatchResult {
+		return BatchResult{Line: line, Status: BatchSucceeded, Id: fmt.Sprintf("id-%d", line)}
+	}
+	failed := func(line int) BatchResult {
+		return BatchResult{Line: line, Status: BatchFailed, Error: errCreate.Error()}
+	}
+	skipped := func(line int) BatchResult {
+		return BatchResult{Line: line, Status: BatchSkipped}
+	}
+
+	tests := []struct {
+		name            string
+		concurrency     int
+		continueOnError bool
+		// the line of the record that fails, if any
+		failLine int
+		dryRun   bool
+		want     []BatchResult
+	}{
+		{
+			name:        "results in the order of the records",
+			concurrency: 3,
+			want:        []BatchResult{succeeded(2), succeeded(3), succeeded(4), succeeded(5), succeeded(6)},
+		},
+		{
+			name:        "skips the records after a failure",
+			concurrency: 1,
+			failLine:    3,
+			want:        []BatchResult{succeeded(2), failed(3), skipped(4), skipped(5), skipped(6)},
+		},
+		{
+			name:            "continues after a failure",
+			concurrency:     1,
+			continueOnError: true,
+			failLine:        3,
+			want:            []BatchResult{succeeded(2), failed(3), succeeded(4), succeeded(5), succeeded(6)},
+		},
+		{
+			name:     "runs one at a time without a concurrency",
+			failLine: 5,
+			want:     []BatchResult{succeeded(2), succeeded(3), succeeded(4), failed(5), skipped(6)},
+		},
+		{
+			name:        "reports a dry run as planned",
+			concurrency: 2,
+			dryRun:      true,
+			want: []BatchResult{
+				{Line: 2, Status: BatchPlanned}, {Line: 3, Status: BatchPlanned}, {Line: 4, Status: BatchPlanned},
+				{Line: 5, Status: BatchPlanned}, {Line: 6, Status: BatchPlanned},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunBatch(records, tt.concurrency, tt.continueOnError, func(r BatchRecord) (string, error) {
+				// earlier records finish last, so results completing out of order are still reported in order
+				time.Sleep(time.Duration(len(records)+2-r.Line) * time.Millisecond)
+				switch {
+				case tt.dryRun:
+					return "", &handlers.DryRunError{Rpc: "Create"}
+				case r.Line == tt.failLine:
+					return "", errCreate
+				}
+				return fmt.Sprintf("id-%d", r.Line), nil
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 )
@@ -15,6 +16,14 @@ const (
 	ActionUpdate     = "update"
 	ActionDeactivate = "deactivate"
 	ActionDelete     = "delete"
+	ActionApply      = "apply"
+	ActionDiff       = "diff"
+	ActionTest       = "test"
+	ActionSimulate   = "simulate"
+	ActionDecide     = "decide"
+	ActionMatch      = "match"
+	ActionLint       = "lint"
+	ActionDependents = "dependents"
 
 	// member actions
 	ActionMemberAdd     = "add members"
@@ -22,10 +31,16 @@ const (
 	ActionMemberReplace = "replace all existing members"
 )
 
-func ConfirmAction(action, resource, id string) {
+// ConfirmAction prompts before an action, listing any objects that depend on the resource and would be stranded
+func ConfirmAction(action, resource, id string, dependents ...string) {
 	var confirm bool
+	description := ""
+	if len(dependents) > 0 {
+		description = fmt.Sprintf("%d objects depend on it:\n\n\t%s\n", len(dependents), strings.Join(dependents, "\n\t"))
+	}
 	err := huh.NewConfirm().
 		Title(fmt.Sprintf("Are you sure you want to %s %s:\n\n\t%s", action, resource, id)).
+		Description(description).
 		Affirmative("yes").
 		Negative("no").
 		Value(&confirm).
@@ -1,19 +1,23 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/opentdf/otdfctl/pkg/handlers"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// ExitWithError prints the message, with the error if there is one, and exits. Callers also use it for invalid
+// flags, where there is no underlying error.
 func ExitWithError(errMsg string, err error) {
+	ExitWithDryRun(err)
 	ExitWithNotFoundError(errMsg, err)
-	if err != nil {
-		fmt.Println(ErrorMessage(errMsg, err))
-		os.Exit(1)
-	}
+	fmt.Println(ErrorMessage(errMsg, err))
+	os.Exit(1)
 }
 
 func ExitWithNotFoundError(errMsg string, err error) {
@@ -22,3 +26,16 @@ func ExitWithNotFoundError(errMsg string, err error) {
 		os.Exit(1)
 	}
 }
+
+// Prints the planned request and current server state of a dry-run mutation, then exits successfully as nothing was sent
+func ExitWithDryRun(err error) {
+	var plan *handlers.DryRunError
+	if errors.As(err, &plan) {
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			ExitWithError("Failed to marshal dry run plan", err)
+		}
+		fmt.Println(string(output))
+		os.Exit(0)
+	}
+}
@@ -35,6 +35,11 @@ func (f FlagHelper) GetOptionalString(flag string) string {
 	return f.cmd.Flag(flag).Value.String()
 }
 
+func (f FlagHelper) GetOptionalBool(flag string) bool {
+	v, _ := f.cmd.Flags().GetBool(flag)
+	return v
+}
+
 func (f FlagHelper) GetStringSlice(flag string, v []string, opts FlagHelperStringSliceOptions) []string {
 	if len(v) < opts.Min {
 		fmt.Println(ErrorMessage(fmt.Sprintf("Flag %s must have at least %d non-empty values", flag, opts.Min), nil))
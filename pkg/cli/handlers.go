@@ -1,14 +1,43 @@
 package cli
 
 import (
+	"fmt"
+
+	"github.com/opentdf/otdfctl/pkg/grpc"
 	"github.com/opentdf/otdfctl/pkg/handlers"
 	"github.com/spf13/cobra"
 )
 
 func NewHandler(cmd *cobra.Command) handlers.Handler {
-	h, err := handlers.New(cmd.Flag("host").Value.String())
+	h := NewHandlerForHost(cmd, cmd.Flag("host").Value.String())
+	if f := cmd.Flag("dry-run"); f != nil {
+		h.DryRun = f.Value.String() == "true"
+	}
+	return h
+}
+
+// Connects to a platform other than the one given by the global --host flag, with the same TLS settings
+func NewHandlerForHost(cmd *cobra.Command, host string) handlers.Handler {
+	actAs := ""
+	if f := cmd.Flag("act-as"); f != nil {
+		actAs = f.Value.String()
+	}
+	h, err := handlers.New(host, GetTLSConfig(cmd), actAs)
 	if err != nil {
-		ExitWithError("Failed to connect to server", err)
+		ExitWithError(fmt.Sprintf("Failed to connect to server (%s)", host), err)
 	}
 	return h
 }
+
+// Reads the global TLS flags
+func GetTLSConfig(cmd *cobra.Command) grpc.TLSConfig {
+	flagHelper := NewFlagHelper(cmd)
+	return grpc.TLSConfig{
+		Plaintext:          flagHelper.GetOptionalBool("plaintext"),
+		CAFile:             flagHelper.GetOptionalString("tls-ca-file"),
+		CertFile:           flagHelper.GetOptionalString("tls-cert-file"),
+		KeyFile:            flagHelper.GetOptionalString("tls-key-file"),
+		ServerName:         flagHelper.GetOptionalString("tls-server-name"),
+		InsecureSkipVerify: flagHelper.GetOptionalBool("insecure-skip-verify"),
+	}
+}
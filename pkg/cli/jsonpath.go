@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EvalJSONPath evaluates a JSONPath expression against a decoded JSON value, returning every match. Supported are
+// the root '$', children '.name' and ['name'], indexes [n] (negative from the end), wildcards '.*' and [*],
+// recursive descent '..name' and equality filters [?(@.field=='value')]. A kubectl style '{...}' wrapper is allowed.
+func EvalJSONPath(expr string, v interface{}) ([]interface{}, error) {
+	path := strings.TrimSpace(expr)
+	if strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}") {
+		path = strings.TrimSpace(path[1 : len(path)-1])
+	}
+	path = strings.TrimPrefix(path, "$")
+
+	nodes := []interface{}{v}
+	for path != "" {
+		var err error
+		switch {
+		case strings.HasPrefix(path, ".."):
+			name, rest := splitJSONPathName(path[2:])
+			if name == "" {
+				return nil, fmt.Errorf("invalid jsonpath '%s': '..' must be followed by a field name", expr)
+			}
+			next := []interface{}{}
+			for _, n := range nodes {
+				next = append(next, descendJSONPath(n, name)...)
+			}
+			nodes, path = next, rest
+		case strings.HasPrefix(path, "."):
+			name, rest := splitJSONPathName(path[1:])
+			if name == "" {
+				return nil, fmt.Errorf("invalid jsonpath '%s': '.' must be followed by a field name", expr)
+			}
+			nodes, path = childrenJSONPath(nodes, name), rest
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if strings.HasPrefix(path, "[?(") {
+				end = strings.Index(path, ")]") + 1
+			}
+			if end <= 0 {
+				return nil, fmt.Errorf("invalid jsonpath '%s': unclosed '['", expr)
+			}
+			if nodes, err = subscriptJSONPath(nodes, path[1:end]); err != nil {
+				return nil, fmt.Errorf("invalid jsonpath '%s': %w", expr, err)
+			}
+			path = path[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid jsonpath '%s': unexpected '%s'", expr, path)
+		}
+	}
+	return nodes, nil
+}
+
+func splitJSONPathName(path string) (string, string) {
+	end := strings.IndexAny(path, ".[")
+	if end < 0 {
+		return path, ""
+	}
+	return path[:end], path[end:]
+}
+
+func childrenJSONPath(nodes []interface{}, name string) []interface{} {
+	next := []interface{}{}
+	for _, n := range nodes {
+		if name == "*" {
+			next = append(next, jsonPathValues(n)...)
+			continue
+		}
+		if m, ok := n.(map[string]interface{}); ok {
+			if c, ok := m[name]; ok {
+				next = append(next, c)
+			}
+		}
+	}
+	return next
+}
+
+func subscriptJSONPath(nodes []interface{}, sub string) ([]interface{}, error) {
+	sub = strings.TrimSpace(sub)
+	switch {
+	case sub == "*":
+		return childrenJSONPath(nodes, "*"), nil
+	case strings.HasPrefix(sub, "'") || strings.HasPrefix(sub, `"`):
+		return childrenJSONPath(nodes, strings.Trim(sub, `'"`)), nil
+	case strings.HasPrefix(sub, "?(") && strings.HasSuffix(sub, ")"):
+		field, want, ok := strings.Cut(sub[2:len(sub)-1], "==")
+		field = strings.TrimSpace(field)
+		if !ok || !strings.HasPrefix(field, "@.") {
+			return nil, fmt.Errorf("filter '%s' must be in the format ?(@.field=='value')", sub)
+		}
+		want = strings.Trim(strings.TrimSpace(want), `'"`)
+		next := []interface{}{}
+		for _, n := range nodes {
+			for _, item := range jsonPathValues(n) {
+				matches, err := EvalJSONPath("$"+field[1:], item)
+				if err != nil {
+					return nil, err
+				}
+				for _, m := range matches {
+					if jsonScalar(m) == want {
+						next = append(next, item)
+						break
+					}
+				}
+			}
+		}
+		return next, nil
+	default:
+		i, err := strconv.Atoi(sub)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported subscript '[%s]'", sub)
+		}
+		next := []interface{}{}
+		for _, n := range nodes {
+			if l, ok := n.([]interface{}); ok {
+				j := i
+				if j < 0 {
+					j += len(l)
+				}
+				if j >= 0 && j < len(l) {
+					next = append(next, l[j])
+				}
+			}
+		}
+		return next, nil
+	}
+}
+
+// descendJSONPath finds the named field at any depth
+func descendJSONPath(n interface{}, name string) []interface{} {
+	found := []interface{}{}
+	if m, ok := n.(map[string]interface{}); ok {
+		if c, ok := m[name]; ok {
+			found = append(found, c)
+		}
+	}
+	for _, c := range jsonPathValues(n) {
+		found = append(found, descendJSONPath(c, name)...)
+	}
+	return found
+}
+
+// the elements of a list, or the values of an object ordered by key
+func jsonPathValues(n interface{}) []interface{} {
+	switch c := n.(type) {
+	case []interface{}:
+		return c
+	case map[string]interface{}:
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			values = append(values, c[k])
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testJSONPathDocument = `{
+	"name": "example.com",
+	"id": "ns-1",
+	"attributes": [
+		{"name": "level", "rule": "HIERARCHY", "values": [{"value": "high", "id": "v-1"}, {"value": "low", "id": "v-2"}]},
+		{"name": "team", "rule": "ANY_OF", "values": [{"value": "eng", "id": "v-3"}]},
+		{"name": "empty", "rule": "ALL_OF"}
+	],
+	"metadata": {"labels": {"env": "dev", "team": "a"}},
+	"key.with.dots": true
+}`
+
+func TestEvalJSONPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(testJSONPathDocument), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want []interface{}
+		// the expected error, where empty is success
+		err string
+	}{
+		{expr: "$", want: []interface{}{doc}},
+		{expr: "", want: []interface{}{doc}},
+		{expr: "$.name", want: []interface{}{"example.com"}},
+		{expr: ".name", want: []interface{}{"example.com"}},
+		{expr: "{.name}", want: []interface{}{"example.com"}},
+		{expr: " { $.id } ", want: []interface{}{"ns-1"}},
+		{expr: "$['name']", want: []interface{}{"example.com"}},
+		{expr: `$["key.with.dots"]`, want: []interface{}{true}},
+		{expr: "$.metadata.labels.team", want: []interface{}{"a"}},
+		{expr: "$.attributes[0].name", want: []interface{}{"level"}},
+		{expr: "$.attributes[ 1 ].name", want: []interface{}{"team"}},
+		{expr: "$.attributes[-1].name", want: []interface{}{"empty"}},
+		{expr: "$.attributes[-3].name", want: []interface{}{"level"}},
+		{expr: "$.attributes[3].name", want: []interface{}{}},
+		{expr: "$.attributes[-4].name", want: []interface{}{}},
+		{expr: "$.attributes[*].name", want: []interface{}{"level", "team", "empty"}},
+		{expr: "$.attributes.*.rule", want: []interface{}{"HIERARCHY", "ANY_OF", "ALL_OF"}},
+		{expr: "$.attributes[*].values[*].value", want: []interface{}{"high", "low", "eng"}},
+		// object values are visited in key order
+		{expr: "$.metadata.labels[*]", want: []interface{}{"dev", "a"}},
+		{expr: "$..value", want: []interface{}{"high", "low", "eng"}},
+		{expr: "$.attributes[0]..id", want: []interface{}{"v-1", "v-2"}},
+		{expr: "$..team", want: []interface{}{"a"}},
+		{expr: "$..missing", want: []interface{}{}},
+		{expr: "$.attributes[?(@.rule=='ANY_OF')].name", want: []interface{}{"team"}},
+		{expr: `$.attributes[?(@.name == "level")].values[1].value`, want: []interface{}{"low"}},
+		{expr: "$.attributes[?(@.values[*].value=='eng')].name", want: []interface{}{"team"}},
+		{expr: "$.missing.name", want: []interface{}{}},
+		{expr: "$.name[0]", want: []interface{}{}},
+		{expr: "$..", err: "'..' must be followed by a field name"},
+		{expr: "$.", err: "'.' must be followed by a field name"},
+		{expr: "$.attributes[0", err: "unclosed '['"},
+		{expr: "$.attributes[?(@.rule=='ANY_OF']", err: "unclosed '['"},
+		{expr: "$.attributes[first]", err: "unsupported subscript '[first]'"},
+		{expr: "$.attributes[?(@.rule)]", err: "must be in the format ?(@.field=='value')"},
+		{expr: "$.attributes[?(rule=='ANY_OF')]", err: "must be in the format ?(@.field=='value')"},
+		{expr: "name", err: "unexpected 'name'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalJSONPath(tt.expr, doc)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v (%v)", tt.err, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
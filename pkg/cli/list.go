@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// ListOptions are the global --limit, --offset, --page-token, --sort-by and --filter flags, which every list
+// command applies the same way to the columns of its table
+type ListOptions struct {
+	Limit  int
+	Offset int
+	// column to sort by, descending when given with a '-' prefix
+	SortBy     string
+	Descending bool
+	Filters    []ListFilter
+}
+
+// ListFilter matches the rows whose column equals (=), does not equal (!=) or contains (~=) the value, ignoring case
+type ListFilter struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ListPage is the part of a list selected by the ListOptions
+type ListPage struct {
+	// indexes of the selected items, in the order they are shown
+	Indexes []int
+	Rows    [][]string
+	// number of items matching the filters, across all pages
+	Total  int
+	Offset int
+	// token of the following page, empty on the last page
+	NextPageToken string
+}
+
+var listFilterOperators = []string{"!=", "~=", "="}
+
+// Reads the global list flags, exiting on invalid values
+func GetListOptions(cmd *cobra.Command) ListOptions {
+	opts := ListOptions{}
+	if f := cmd.Flag("limit"); f != nil {
+		opts.Limit, _ = strconv.Atoi(f.Value.String())
+	}
+	if f := cmd.Flag("offset"); f != nil {
+		opts.Offset, _ = strconv.Atoi(f.Value.String())
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		ExitWithError("Flags limit and offset must not be negative", nil)
+	}
+	if f := cmd.Flag("page-token"); f != nil && f.Value.String() != "" {
+		if cmd.Flags().Changed("offset") {
+			ExitWithError("Flags offset and page-token cannot be combined", nil)
+		}
+		offset, err := decodePageToken(f.Value.String())
+		if err != nil {
+			ExitWithError("Invalid page token", err)
+		}
+		opts.Offset = offset
+	}
+	if f := cmd.Flag("sort-by"); f != nil {
+		opts.SortBy = f.Value.String()
+		if strings.HasPrefix(opts.SortBy, "-") {
+			opts.SortBy = strings.TrimPrefix(opts.SortBy, "-")
+			opts.Descending = true
+		}
+	}
+	if f := cmd.Flag("filter"); f != nil && f.Value.String() != "" {
+		filters, err := ParseListFilters(f.Value.String())
+		if err != nil {
+			ExitWithError("Invalid filter", err)
+		}
+		opts.Filters = filters
+	}
+	return opts
+}
+
+// ParseListFilters parses a filter expression such as 'namespace=example.com,active=true'
+func ParseListFilters(expr string) ([]ListFilter, error) {
+	var filters []ListFilter
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		filter := ListFilter{}
+		for _, op := range listFilterOperators {
+			if i := strings.Index(term, op); i > 0 {
+				filter = ListFilter{Column: strings.TrimSpace(term[:i]), Operator: op, Value: strings.TrimSpace(term[i+len(op):])}
+				break
+			}
+		}
+		if filter.Operator == "" {
+			return nil, fmt.Errorf("'%s' must be in the format column=value, column!=value or column~=value", term)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// PageList filters, sorts and pages the rows of a list table, exiting when a filter or sort column is not one of
+// the headers. Columns are matched ignoring case, spaces and punctuation, so 'attribute-value-id' names the
+// 'Attribute Value Id' column.
+func PageList(opts ListOptions, headers []string, rows [][]string) ListPage {
+	columns := map[string]int{}
+	for i, h := range headers {
+		columns[normalizeColumn(h)] = i
+	}
+	column := func(name string) int {
+		i, ok := columns[normalizeColumn(name)]
+		if !ok {
+			ExitWithError(fmt.Sprintf("Unknown column '%s', must be one of [%s]", name, strings.Join(headers, ", ")), nil)
+		}
+		return i
+	}
+
+	indexes := []int{}
+	for i, row := range rows {
+		if matchesListFilters(opts.Filters, column, row) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if opts.SortBy != "" {
+		c := column(opts.SortBy)
+		sort.SliceStable(indexes, func(a, b int) bool {
+			x, y := rows[indexes[a]][c], rows[indexes[b]][c]
+			if opts.Descending {
+				x, y = y, x
+			}
+			return lessListValue(x, y)
+		})
+	}
+
+	page := ListPage{Total: len(indexes), Offset: opts.Offset}
+	start := min(opts.Offset, len(indexes))
+	end := len(indexes)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+		page.NextPageToken = encodePageToken(end)
+	}
+	page.Indexes = indexes[start:end]
+	for _, i := range page.Indexes {
+		page.Rows = append(page.Rows, rows[i])
+	}
+	return page
+}
+
+// PageItems selects the items of the page from the list the rows were built from, i.e. for JSON output
+func PageItems[T any](items []T, page ListPage) []T {
+	selected := make([]T, 0, len(page.Indexes))
+	for _, i := range page.Indexes {
+		selected = append(selected, items[i])
+	}
+	return selected
+}
+
+// Describes which items of the list are shown, and how to get the following page
+func (p ListPage) Footer() string {
+	if len(p.Rows) == 0 {
+		return fmt.Sprintf("Showing 0 of %d", p.Total)
+	}
+	footer := fmt.Sprintf("Showing %d-%d of %d", p.Offset+1, p.Offset+len(p.Rows), p.Total)
+	if p.NextPageToken != "" {
+		footer += fmt.Sprintf(", next page with --page-token=%s", p.NextPageToken)
+	}
+	return footer
+}
+
+func matchesListFilters(filters []ListFilter, column func(string) int, row []string) bool {
+	for _, f := range filters {
+		v := strings.ToLower(row[column(f.Column)])
+		want := strings.ToLower(f.Value)
+		switch f.Operator {
+		case "=":
+			if v != want {
+				return false
+			}
+		case "!=":
+			if v == want {
+				return false
+			}
+		case "~=":
+			if !strings.Contains(v, want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// numbers sort numerically, everything else alphabetically ignoring case
+func lessListValue(x, y string) bool {
+	if a, err := strconv.ParseFloat(x, 64); err == nil {
+		if b, err := strconv.ParseFloat(y, 64); err == nil {
+			return a < b
+		}
+	}
+	return strings.ToLower(x) < strings.ToLower(y)
+}
+
+func normalizeColumn(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// page tokens are opaque to users, but simply encode the offset of the page as the platform lists everything at once
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), "offset:"))
+	if err != nil || !strings.HasPrefix(string(b), "offset:") || offset < 0 {
+		return 0, fmt.Errorf("'%s' is not a page token", token)
+	}
+	return offset, nil
+}
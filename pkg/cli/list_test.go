@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseListFilters(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []ListFilter
+		err  bool
+	}{
+		{expr: "name=example.com", want: []ListFilter{{Column: "name", Operator: "=", Value: "example.com"}}},
+		{expr: "active != false", want: []ListFilter{{Column: "active", Operator: "!=", Value: "false"}}},
+		{expr: "fqn~=attr/level", want: []ListFilter{{Column: "fqn", Operator: "~=", Value: "attr/level"}}},
+		{
+			expr: " namespace=example.com , ,active=true,",
+			want: []ListFilter{{Column: "namespace", Operator: "=", Value: "example.com"}, {Column: "active", Operator: "=", Value: "true"}},
+		},
+		// only the first operator splits the term
+		{expr: "terms~=a=b", want: []ListFilter{{Column: "terms", Operator: "~=", Value: "a=b"}}},
+		{expr: "name=", want: []ListFilter{{Column: "name", Operator: "=", Value: ""}}},
+		{expr: "", want: nil},
+		{expr: "name", err: true},
+		{expr: "=value", err: true},
+		{expr: "name=a,active", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseListFilters(tt.expr)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPageList(t *testing.T) {
+	headers := []string{"Id", "Name", "Value Count"}
+	rows := [][]string{
+		{"1", "beta", "10"},
+		{"2", "Alpha", "9"},
+		{"3", "gamma", "100"},
+		{"4", "delta", "9"},
+	}
+	names := func(page ListPage) []string {
+		names := []string{}
+		for _, row := range page.Rows {
+			names = append(names, row[1])
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		opts  ListOptions
+		want  []string
+		total int
+		// whether the page is followed by another
+		next bool
+	}{
+		{name: "everything in list order", want: []string{"beta", "Alpha", "gamma", "delta"}, total: 4},
+		{name: "sorted ignoring case", opts: ListOptions{SortBy: "name"}, want: []string{"Alpha", "beta", "delta", "gamma"}, total: 4},
+		{name: "sorted descending", opts: ListOptions{SortBy: "name", Descending: true}, want: []string{"gamma", "delta", "beta", "Alpha"}, total: 4},
+		// equal values keep their list order
+		{name: "sorted numerically by a normalized column", opts: ListOptions{SortBy: "value-count"}, want: []string{"Alpha", "delta", "beta", "gamma"}, total: 4},
+		{name: "filtered by equality ignoring case", opts: ListOptions{Filters: []ListFilter{{Column: "name", Operator: "=", Value: "ALPHA"}}}, want: []string{"Alpha"}, total: 1},
+		{name: "filtered by inequality", opts: ListOptions{Filters: []ListFilter{{Column: "value count", Operator: "!=", Value: "9"}}}, want: []string{"beta", "gamma"}, total: 2},
+		{name: "filtered by substring", opts: ListOptions{Filters: []ListFilter{{Column: "Name", Operator: "~=", Value: "ta"}}}, want: []string{"beta", "delta"}, total: 2},
+		{name: "first page", opts: ListOptions{Limit: 3}, want: []string{"beta", "Alpha", "gamma"}, total: 4, next: true},
+		{name: "last page", opts: ListOptions{Limit: 3, Offset: 3}, want: []string{"delta"}, total: 4},
+		{name: "page ending at the last item", opts: ListOptions{Limit: 2, Offset: 2}, want: []string{"gamma", "delta"}, total: 4},
+		{name: "offset past the end", opts: ListOptions{Offset: 10}, want: []string{}, total: 4},
+		{name: "page of the sorted and filtered list", opts: ListOptions{Limit: 1, Offset: 1, SortBy: "name", Filters: []ListFilter{{Column: "name", Operator: "~=", Value: "a"}}}, want: []string{"beta"}, total: 4, next: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := PageList(tt.opts, headers, rows)
+			if got := names(page); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if page.Total != tt.total {
+				t.Errorf("expected total %d, got %d", tt.total, page.Total)
+			}
+			if (page.NextPageToken != "") != tt.next {
+				t.Fatalf("expected a next page %t, got token %q", tt.next, page.NextPageToken)
+			}
+			if got := PageItems(rows, page); len(got) != len(page.Rows) || (len(got) > 0 && !reflect.DeepEqual(got, page.Rows)) {
+				t.Errorf("expected the items of the page %v, got %v", page.Rows, got)
+			}
+			if !tt.next {
+				return
+			}
+
+			// the next page starts where this one ends
+			offset, err := decodePageToken(page.NextPageToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if offset != tt.opts.Offset+len(page.Rows) {
+				t.Errorf("expected the next page at offset %d, got %d", tt.opts.Offset+len(page.Rows), offset)
+			}
+			if footer := page.Footer(); !strings.Contains(footer, "--page-token="+page.NextPageToken) {
+				t.Errorf("expected the footer to show the next page token, got %q", footer)
+			}
+		})
+	}
+}
+
+func TestListPageFooter(t *testing.T) {
+	tests := []struct {
+		page ListPage
+		want string
+	}{
+		{page: ListPage{Total: 0}, want: "Showing 0 of 0"},
+		{page: ListPage{Total: 4, Offset: 10}, want: "Showing 0 of 4"},
+		{page: ListPage{Rows: [][]string{{"a"}, {"b"}}, Total: 2}, want: "Showing 1-2 of 2"},
+		{page: ListPage{Rows: [][]string{{"c"}}, Total: 4, Offset: 2, NextPageToken: "token"}, want: "Showing 3-3 of 4, next page with --page-token=token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.page.Footer(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  int
+		err   bool
+	}{
+		{name: "encoded offset", token: encodePageToken(25), want: 25},
+		{name: "first item", token: encodePageToken(0), want: 0},
+		{name: "not base64", token: "not a token!", err: true},
+		{name: "padded base64", token: encodePageToken(25) + "=", err: true},
+		{name: "missing prefix", token: "MjU", err: true},
+		{name: "not a number", token: "b2Zmc2V0OmFi", err: true},
+		{name: "negative offset", token: "b2Zmc2V0Oi0x", err: true},
+		{name: "empty offset", token: "b2Zmc2V0Og", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePageToken(tt.token)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected an error, got offset %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected offset %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
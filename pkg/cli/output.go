@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputStyled   = "styled"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputCSV      = "csv"
+	OutputNDJSON   = "ndjson"
+	OutputTemplate = "template"
+	OutputJSONPath = "jsonpath"
+)
+
+// OutputFormatHelp lists the accepted values of --output and 'config output'
+const OutputFormatHelp = "styled, json, yaml, csv, ndjson, template=<go-template> or jsonpath=<expr>"
+
+// OutputFormat is how command results are printed, with the expression of the template and jsonpath formats
+type OutputFormat struct {
+	Kind string
+	Expr string
+}
+
+func (f OutputFormat) String() string {
+	if f.Expr == "" {
+		return f.Kind
+	}
+	return f.Kind + "=" + f.Expr
+}
+
+// ParseOutputFormat parses one of OutputFormatHelp, where empty is styled
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	kind, expr, hasExpr := strings.Cut(s, "=")
+	f := OutputFormat{Kind: strings.ToLower(strings.TrimSpace(kind)), Expr: expr}
+	switch f.Kind {
+	case "":
+		f.Kind = OutputStyled
+	case OutputStyled, OutputJSON, OutputYAML, OutputCSV, OutputNDJSON:
+		if hasExpr {
+			return OutputFormat{}, fmt.Errorf("output format '%s' takes no expression", f.Kind)
+		}
+	case OutputTemplate, OutputJSONPath:
+		if strings.TrimSpace(expr) == "" {
+			return OutputFormat{}, fmt.Errorf("output format '%s' requires an expression, i.e. %s=<expr>", f.Kind, f.Kind)
+		}
+	default:
+		return OutputFormat{}, fmt.Errorf("invalid output format '%s', must be one of [%s]", s, OutputFormatHelp)
+	}
+	return f, nil
+}
+
+// PrintOutput prints the object in any format but styled, which commands render as tables themselves. Every
+// format works on the object's JSON form, so field names are the same as in the json output.
+func PrintOutput(f OutputFormat, obj interface{}) error {
+	if f.Kind == OutputJSON {
+		output, err := json.MarshalIndent(protoJSONValue(obj), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	v, err := toJSONValue(obj)
+	if err != nil {
+		return err
+	}
+	switch f.Kind {
+	case OutputYAML:
+		output, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(output))
+	case OutputNDJSON:
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		for _, item := range items {
+			line, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(line))
+		}
+	case OutputCSV:
+		return printCSV(v)
+	case OutputTemplate:
+		tmpl, err := template.New("output").Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(f.Expr)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, v); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		fmt.Print(buf.String())
+	case OutputJSONPath:
+		results, err := EvalJSONPath(f.Expr, v)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			fmt.Println(jsonScalar(r))
+		}
+	default:
+		return fmt.Errorf("output format '%s' cannot print this object", f.Kind)
+	}
+	return nil
+}
+
+// MarshalJSON marshals protobuf messages with protojson, also within slices and maps, and anything else with
+// encoding/json. Protojson keeps the JSON names, oneofs, enums (as strings) and well-known types of the API, so
+// the output can be fed back as input.
+func MarshalJSON(obj interface{}) ([]byte, error) {
+	return json.Marshal(protoJSONValue(obj))
+}
+
+func protoJSONValue(obj interface{}) interface{} {
+	if obj == nil {
+		return nil
+	}
+	if m, ok := obj.(proto.Message); ok {
+		if !m.ProtoReflect().IsValid() {
+			return nil
+		}
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return obj
+		}
+		return json.RawMessage(b)
+	}
+
+	v := reflect.ValueOf(obj)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return obj
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = protoJSONValue(v.Index(i).Interface())
+		}
+		return items
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.IsNil() {
+			return obj
+		}
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[iter.Key().String()] = protoJSONValue(iter.Value().Interface())
+		}
+		return entries
+	}
+	return obj
+}
+
+func toJSONValue(obj interface{}) (interface{}, error) {
+	b, err := MarshalJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return normalizeJSONNumbers(v), nil
+}
+
+// whole numbers are kept as integers, so large ones do not print in exponent notation
+func normalizeJSONNumbers(v interface{}) interface{} {
+	switch c := v.(type) {
+	case json.Number:
+		if i, err := c.Int64(); err == nil {
+			return i
+		}
+		f, _ := c.Float64()
+		return f
+	case []interface{}:
+		for i := range c {
+			c[i] = normalizeJSONNumbers(c[i])
+		}
+	case map[string]interface{}:
+		for k := range c {
+			c[k] = normalizeJSONNumbers(c[k])
+		}
+	}
+	return v
+}
+
+// printCSV prints a list as one row per item, or any other object as a single row. The columns are the top-level
+// fields, id first and the rest alphabetically, with nested fields written as JSON.
+func printCSV(v interface{}) error {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+
+	fields := map[string]bool{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			for k := range m {
+				fields[k] = true
+			}
+		}
+	}
+	headers := make([]string, 0, len(fields))
+	for k := range fields {
+		headers = append(headers, k)
+	}
+	sort.Slice(headers, func(i, j int) bool {
+		if headers[i] == "id" || headers[j] == "id" {
+			return headers[i] == "id"
+		}
+		return headers[i] < headers[j]
+	})
+	if len(headers) == 0 {
+		headers = []string{"value"}
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, item := range items {
+		row := make([]string, len(headers))
+		if m, ok := item.(map[string]interface{}); ok {
+			for i, h := range headers {
+				row[i] = jsonScalar(m[h])
+			}
+		} else {
+			row[0] = jsonScalar(item)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// jsonScalar writes strings, numbers and booleans as plain text and anything else as compact JSON
+func jsonScalar(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case int64:
+		return strconv.FormatInt(s, 10)
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	case bool:
+		return fmt.Sprint(s)
+	default:
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		s    string
+		want OutputFormat
+		err  string
+	}{
+		{s: "", want: OutputFormat{Kind: OutputStyled}},
+		{s: "styled", want: OutputFormat{Kind: OutputStyled}},
+		{s: " JSON ", want: OutputFormat{Kind: OutputJSON}},
+		{s: "yaml", want: OutputFormat{Kind: OutputYAML}},
+		{s: "csv", want: OutputFormat{Kind: OutputCSV}},
+		{s: "ndjson", want: OutputFormat{Kind: OutputNDJSON}},
+		{s: "template={{.id}}", want: OutputFormat{Kind: OutputTemplate, Expr: "{{.id}}"}},
+		// only the first '=' separates the expression
+		{s: "jsonpath=$.items[?(@.name=='a')].id", want: OutputFormat{Kind: OutputJSONPath, Expr: "$.items[?(@.name=='a')].id"}},
+		{s: "json=x", err: "takes no expression"},
+		{s: "template", err: "requires an expression"},
+		{s: "jsonpath= ", err: "requires an expression"},
+		{s: "xml", err: "invalid output format 'xml'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseOutputFormat(tt.s)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v (%v)", tt.err, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+			if reparsed, err := ParseOutputFormat(got.String()); err != nil || reparsed != got {
+				t.Errorf("expected %q to parse back into %+v, got %+v (%v)", got.String(), got, reparsed, err)
+			}
+		})
+	}
+}
+
+// captureStdout returns what the function prints to stdout
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+	err = f()
+	w.Close()
+	return <-output, err
+}
+
+type testOutputItem struct {
+	Id     string            `json:"id"`
+	Name   string            `json:"name"`
+	Count  int64             `json:"count,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func TestPrintOutput(t *testing.T) {
+	items := []testOutputItem{
+		{Id: "1", Name: "plain", Count: 12345678901},
+		{Id: "2", Name: `with "quotes", commas`, Labels: map[string]string{"team": "a"}},
+		{Id: "3", Name: "multi\nline"},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		obj    interface{}
+		want   string
+		err    string
+	}{
+		{
+			name:   "json",
+			format: "json",
+			obj:    items[0],
+			want:   "{\n  \"id\": \"1\",\n  \"name\": \"plain\",\n  \"count\": 12345678901\n}\n",
+		},
+		{
+			name:   "yaml keeps large numbers whole",
+			format: "yaml",
+			obj:    items[0],
+			want:   "count: 12345678901\nid: \"1\"\nname: plain\n",
+		},
+		{
+			name:   "ndjson of a list",
+			format: "ndjson",
+			obj:    items[:2],
+			want:   "{\"count\":12345678901,\"id\":\"1\",\"name\":\"plain\"}\n{\"id\":\"2\",\"labels\":{\"team\":\"a\"},\"name\":\"with \\\"quotes\\\", commas\"}\n",
+		},
+		{
+			name:   "ndjson of a single object",
+			format: "ndjson",
+			obj:    items[2],
+			want:   "{\"id\":\"3\",\"name\":\"multi\\nline\"}\n",
+		},
+		{
+			name:   "csv quotes fields and writes nested fields as JSON",
+			format: "csv",
+			obj:    items,
+			want:   "id,count,labels,name\n1,12345678901,,plain\n2,,\"{\"\"team\"\":\"\"a\"\"}\",\"with \"\"quotes\"\", commas\"\n3,,,\"multi\nline\"\n",
+		},
+		{
+			name:   "csv of scalars",
+			format: "csv",
+			obj:    []string{"a,b", "c"},
+			want:   "value\n\"a,b\"\nc\n",
+		},
+		{
+			name:   "template",
+			format: "template={{range .}}{{.id}}={{.name}} {{json .labels}};{{end}}",
+			obj:    items[:2],
+			want:   "1=plain null;2=with \"quotes\", commas {\"team\":\"a\"};\n",
+		},
+		{
+			name:   "invalid template",
+			format: "template={{.id",
+			obj:    items[0],
+			err:    "invalid template",
+		},
+		{
+			name:   "jsonpath",
+			format: "jsonpath=$[*].labels",
+			obj:    items,
+			want:   "{\"team\":\"a\"}\n",
+		},
+		{
+			name:   "jsonpath of scalars",
+			format: "jsonpath={$[?(@.id=='1')].count}",
+			obj:    items,
+			want:   "12345678901\n",
+		},
+		{
+			name:   "invalid jsonpath",
+			format: "jsonpath=$[x]",
+			obj:    items,
+			err:    "unsupported subscript",
+		},
+		{
+			name:   "styled",
+			format: "styled",
+			obj:    items,
+			err:    "cannot print this object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseOutputFormat(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := captureStdout(t, func() error { return PrintOutput(f, tt.obj) })
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected\n%q\ngot\n%q", tt.want, got)
+			}
+		})
+	}
+}
@@ -41,9 +41,33 @@ func PrintSuccessTable(cmd *cobra.Command, id string, t *table.Table) {
 	case ActionDeactivate:
 		msg.verb = fmt.Sprintf("Deactivated %s: %s", resource, id)
 		msg.helper = getJsonHelper(resource + " list") // TODO: make sure the filters are provided here to get ACTIVE/INACTIVE/ANY
+	case ActionApply:
+		msg.verb = fmt.Sprintf("Applied %s manifest", resource)
+		msg.helper = ""
+	case ActionDiff:
+		msg.verb = fmt.Sprintf("Compared %s", resource)
+		msg.helper = getJsonHelper(resource + " diff --from=<host-or-file> --to=<host-or-file>")
 	case ActionList:
 		msg.verb = fmt.Sprintf("Found %s list", resource)
 		msg.helper = getJsonHelper(resource + " get --id=<id>")
+	case ActionTest:
+		msg.verb = fmt.Sprintf("Tested %s: %s", resource, id)
+		msg.helper = getJsonHelper(resource + " test --id=" + id + " --entity=<file>")
+	case ActionSimulate:
+		msg.verb = fmt.Sprintf("Simulated %s", resource)
+		msg.helper = getJsonHelper(resource + " simulate --entity=<file>")
+	case ActionDecide:
+		msg.verb = "Simulated access decision"
+		msg.helper = getJsonHelper(resource + " decide --entity=<file> --resource-attrs=<fqn,...>")
+	case ActionMatch:
+		msg.verb = fmt.Sprintf("Matched %s", resource)
+		msg.helper = getJsonHelper(resource + " match --text=<text>")
+	case ActionLint:
+		msg.verb = fmt.Sprintf("Linted %s", resource)
+		msg.helper = getJsonHelper(resource + " lint --manifest=<file>")
+	case ActionDependents:
+		msg.verb = fmt.Sprintf("Found dependents of %s", id)
+		msg.helper = getJsonHelper(resource + " dependents --id=" + id)
 	default:
 		msg.verb = ""
 		msg.helper = ""
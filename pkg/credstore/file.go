@@ -0,0 +1,168 @@
+package credstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters recommended for interactive use
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// fileStore keeps all credentials in one file, encrypted with AES-256-GCM under a key derived from the passphrase
+// with scrypt. Every write re-encrypts the whole file and replaces it atomically.
+type fileStore struct {
+	path       string
+	passphrase string
+
+	mu sync.Mutex
+}
+
+// the file contents; the plaintext is the JSON of service to key to value
+type encryptedFile struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (s *fileStore) Get(service, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	v, ok := entries[service][key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *fileStore) Set(service, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+	if entries[service] == nil {
+		entries[service] = map[string]string{}
+	}
+	entries[service][key] = value
+	return s.write(entries, salt)
+}
+
+func (s *fileStore) Delete(service, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[service][key]; !ok {
+		return ErrNotFound
+	}
+	delete(entries[service], key)
+	if len(entries[service]) == 0 {
+		delete(entries, service)
+	}
+	return s.write(entries, salt)
+}
+
+// read decrypts the file, returning no entries and no salt when it does not exist yet
+func (s *fileStore) read() (map[string]map[string]string, []byte, error) {
+	entries := map[string]map[string]string{}
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := encryptedFile{}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, nil, fmt.Errorf("failed to read credential file %s: %w", s.path, err)
+	}
+	aead, err := s.cipher(f.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt credential file %s, check the passphrase", s.path)
+	}
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to read credential file %s: %w", s.path, err)
+	}
+	return entries, f.Salt, nil
+}
+
+func (s *fileStore) write(entries map[string]map[string]string, salt []byte) error {
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	aead, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(encryptedFile{Salt: salt, Nonce: nonce, Ciphertext: aead.Seal(nil, nonce, plaintext, nil)})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(s.passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
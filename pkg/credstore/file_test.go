@@ -0,0 +1,142 @@
+package credstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFileStore(t *testing.T, passphrase string) (Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "otdfctl", "credentials.enc")
+	store, err := New(Config{Backend: File, File: path, Passphrase: passphrase})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, path
+}
+
+func TestFileStore(t *testing.T) {
+	store, path := newTestFileStore(t, "passphrase")
+
+	if _, err := store.Get("otdfctl platform", "token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before the file exists, got %v", err)
+	}
+	if err := store.Set("otdfctl platform", "token", "secret-token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("otdfctl other", "token", "other-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("expected file mode 0600, got %o", mode)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the credential file to remain after writing, got %v", entries)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "secret-token") {
+		t.Error("expected the credentials to be encrypted")
+	}
+
+	// a store opened on the same file with the same passphrase reads what was written
+	reopened, err := New(Config{Backend: File, File: path, Passphrase: "passphrase"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := reopened.Get("otdfctl platform", "token"); err != nil || v != "secret-token" {
+		t.Errorf("expected the stored token, got %q (%v)", v, err)
+	}
+	if _, err := reopened.Get("otdfctl platform", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing key, got %v", err)
+	}
+
+	if err := reopened.Delete("otdfctl platform", "token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("otdfctl platform", "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after deleting, got %v", err)
+	}
+	if err := store.Delete("otdfctl platform", "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleting again to fail with ErrNotFound, got %v", err)
+	}
+	if v, err := store.Get("otdfctl other", "token"); err != nil || v != "other-token" {
+		t.Errorf("expected the other service to be kept, got %q (%v)", v, err)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	store, path := newTestFileStore(t, "passphrase")
+	if err := store.Set("otdfctl platform", "token", "secret-token"); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrong, err := New(Config{Backend: File, File: path, Passphrase: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Get("otdfctl platform", "token"); err == nil || !strings.Contains(err.Error(), "check the passphrase") {
+		t.Errorf("expected a decryption error, got %v", err)
+	}
+	if err := wrong.Set("otdfctl platform", "token", "replaced"); err == nil {
+		t.Error("expected writing with the wrong passphrase to fail")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("expected the file to be left unchanged by the wrong passphrase")
+	}
+}
+
+func TestFileStoreCorrupted(t *testing.T) {
+	store, path := newTestFileStore(t, "passphrase")
+	if err := store.Set("otdfctl platform", "token", "secret-token"); err != nil {
+		t.Fatal(err)
+	}
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// flips a byte inside the base64 ciphertext, keeping it valid base64
+	i := strings.Index(string(valid), `"ciphertext":"`) + len(`"ciphertext":"`) + 4
+	tampered := []byte(string(valid))
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	tests := []struct {
+		name     string
+		contents []byte
+		err      string
+	}{
+		{name: "truncated", contents: valid[:len(valid)/2], err: "failed to read credential file"},
+		{name: "empty", contents: []byte{}, err: "failed to read credential file"},
+		{name: "not JSON", contents: []byte("token=secret"), err: "failed to read credential file"},
+		{name: "tampered ciphertext", contents: tampered, err: "failed to decrypt credential file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, tt.contents, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Get("otdfctl platform", "token"); err == nil || !strings.Contains(err.Error(), tt.err) || errors.Is(err, ErrNotFound) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
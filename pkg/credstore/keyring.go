@@ -0,0 +1,34 @@
+package credstore
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+type keyringStore struct{}
+
+// NewKeyring returns the store backed by the OS keychain
+func NewKeyring() Store {
+	return keyringStore{}
+}
+
+func (keyringStore) Get(service, key string) (string, error) {
+	v, err := keyring.Get(service, key)
+	return v, notFound(err)
+}
+
+func (keyringStore) Set(service, key, value string) error {
+	return keyring.Set(service, key, value)
+}
+
+func (keyringStore) Delete(service, key string) error {
+	return notFound(keyring.Delete(service, key))
+}
+
+func notFound(err error) error {
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
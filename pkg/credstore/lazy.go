@@ -0,0 +1,50 @@
+package credstore
+
+import "sync"
+
+// lazyStore opens the configured store on first use, so commands that never read or write credentials run
+// whatever the store's configuration, i.e. without the passphrase of the encrypted file
+type lazyStore struct {
+	cfg Config
+
+	once  sync.Once
+	store Store
+	err   error
+}
+
+// NewLazy returns a store that opens the configured backend when a credential is first read or written. Every
+// call fails with the error of opening it, if any.
+func NewLazy(cfg Config) Store {
+	return &lazyStore{cfg: cfg}
+}
+
+func (s *lazyStore) open() (Store, error) {
+	s.once.Do(func() {
+		s.store, s.err = New(s.cfg)
+	})
+	return s.store, s.err
+}
+
+func (s *lazyStore) Get(service, key string) (string, error) {
+	store, err := s.open()
+	if err != nil {
+		return "", err
+	}
+	return store.Get(service, key)
+}
+
+func (s *lazyStore) Set(service, key, value string) error {
+	store, err := s.open()
+	if err != nil {
+		return err
+	}
+	return store.Set(service, key, value)
+}
+
+func (s *lazyStore) Delete(service, key string) error {
+	store, err := s.open()
+	if err != nil {
+		return err
+	}
+	return store.Delete(service, key)
+}
@@ -0,0 +1,47 @@
+package credstore
+
+import "sync"
+
+type memoryStore struct {
+	mu       sync.Mutex
+	entries  map[string]map[string]string
+	defaults map[string]string
+}
+
+// NewMemory returns a store that keeps credentials for the life of the process. Keys it holds nothing under
+// return the given defaults, whatever the service, so a login can be supplied through the environment.
+func NewMemory(defaults map[string]string) Store {
+	return &memoryStore{entries: map[string]map[string]string{}, defaults: defaults}
+}
+
+func (s *memoryStore) Get(service, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.entries[service][key]; ok {
+		return v, nil
+	}
+	if v, ok := s.defaults[key]; ok && v != "" {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+func (s *memoryStore) Set(service, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries[service] == nil {
+		s.entries[service] = map[string]string{}
+	}
+	s.entries[service][key] = value
+	return nil
+}
+
+func (s *memoryStore) Delete(service, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[service][key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.entries[service], key)
+	return nil
+}
@@ -0,0 +1,65 @@
+package credstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// the OS keychain (macOS Keychain, Windows Credential Manager or the Secret Service over D-Bus)
+	Keyring = "keyring"
+	// a file encrypted with a passphrase, for machines without a keychain
+	File = "file"
+	// process memory seeded from the environment, for CI runners where nothing outlives the process
+	Memory = "memory"
+)
+
+// ErrNotFound is returned by Get when no credential is stored under the service and key
+var ErrNotFound = errors.New("credential not found")
+
+// Store persists credentials as string values under a service and a key
+type Store interface {
+	Get(service, key string) (string, error)
+	Set(service, key, value string) error
+	// Delete removes the credential, returning ErrNotFound when there is none
+	Delete(service, key string) error
+}
+
+// Config selects and configures the backend of a Store
+type Config struct {
+	// one of Keyring, File or Memory, where empty is Keyring
+	Backend string
+	// path of the encrypted file (default is $HOME/.otdfctl/credentials.enc)
+	File string
+	// passphrase the file is encrypted with
+	Passphrase string
+	// values the memory store returns for keys it holds nothing under, whatever the service
+	Defaults map[string]string
+}
+
+// New opens the store of the configured backend
+func New(cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", Keyring:
+		return NewKeyring(), nil
+	case File:
+		if cfg.Passphrase == "" {
+			return nil, errors.New("the encrypted credential file requires a passphrase")
+		}
+		path := cfg.File
+		if path == "" {
+			homedir, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(homedir, ".otdfctl", "credentials.enc")
+		}
+		return &fileStore{path: path, passphrase: cfg.Passphrase}, nil
+	case Memory:
+		return NewMemory(cfg.Defaults), nil
+	default:
+		return nil, fmt.Errorf("unknown credential store '%s', must be one of [%s, %s, %s]", cfg.Backend, Keyring, File, Memory)
+	}
+}
@@ -0,0 +1,103 @@
+package credstore
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		// the expected error, where empty is success
+		err string
+	}{
+		{name: "keyring by default", cfg: Config{}},
+		{name: "memory", cfg: Config{Backend: Memory}},
+		{name: "file", cfg: Config{Backend: File, File: "credentials.enc", Passphrase: "passphrase"}},
+		{name: "file without a passphrase", cfg: Config{Backend: File}, err: "requires a passphrase"},
+		{name: "unknown backend", cfg: Config{Backend: "vault"}, err: "unknown credential store 'vault'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	// defaults are how a login is supplied through the environment
+	store := NewMemory(map[string]string{"token": "env-token", "empty": ""})
+
+	if v, err := store.Get("otdfctl platform", "token"); err != nil || v != "env-token" {
+		t.Errorf("expected the default of any service, got %q (%v)", v, err)
+	}
+	if _, err := store.Get("otdfctl platform", "empty"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an empty default to be not found, got %v", err)
+	}
+	if _, err := store.Get("otdfctl platform", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.Set("otdfctl platform", "token", "stored-token"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := store.Get("otdfctl platform", "token"); err != nil || v != "stored-token" {
+		t.Errorf("expected the stored value over the default, got %q (%v)", v, err)
+	}
+	if v, err := store.Get("otdfctl other", "token"); err != nil || v != "env-token" {
+		t.Errorf("expected other services to still return the default, got %q (%v)", v, err)
+	}
+
+	if err := store.Delete("otdfctl platform", "token"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := store.Get("otdfctl platform", "token"); err != nil || v != "env-token" {
+		t.Errorf("expected the default after deleting, got %q (%v)", v, err)
+	}
+	if err := store.Delete("otdfctl platform", "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleting a default to fail with ErrNotFound, got %v", err)
+	}
+}
+
+func TestLazyStore(t *testing.T) {
+	t.Run("opens the backend on first use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "credentials.enc")
+		store := NewLazy(Config{Backend: File, File: path, Passphrase: "passphrase"})
+		if err := store.Set("otdfctl platform", "token", "secret-token"); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := store.Get("otdfctl platform", "token"); err != nil || v != "secret-token" {
+			t.Errorf("expected the stored token, got %q (%v)", v, err)
+		}
+		if err := store.Delete("otdfctl platform", "token"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("fails every call when the backend cannot be opened", func(t *testing.T) {
+		// creating the store succeeds, as commands that never use credentials must still run
+		store := NewLazy(Config{Backend: File})
+		if _, err := store.Get("otdfctl platform", "token"); err == nil || !strings.Contains(err.Error(), "requires a passphrase") {
+			t.Errorf("expected the error of opening the store, got %v", err)
+		}
+		if err := store.Set("otdfctl platform", "token", "secret-token"); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("expected the error of opening the store, got %v", err)
+		}
+		if err := store.Delete("otdfctl platform", "token"); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("expected the error of opening the store, got %v", err)
+		}
+	})
+}
@@ -4,15 +4,24 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 var Conn *grpc.ClientConn
 var Context context.Context
 
-func Connect(host string) error {
-	var err error
-	Conn, err = grpc.Dial(host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func Connect(host string, tlsConfig TLSConfig) error {
+	cfg, err := tlsConfig.Load()
+	if err != nil {
+		return err
+	}
+	creds := insecure.NewCredentials()
+	if cfg != nil {
+		creds = credentials.NewTLS(cfg)
+	}
+
+	Conn, err = grpc.Dial(host, grpc.WithTransportCredentials(creds))
 	Context = context.Background()
 	return err
 }
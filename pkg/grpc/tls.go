@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig describes the transport security of the connection to the platform. TLS verified against the
+// system roots is the default.
+type TLSConfig struct {
+	// connect without TLS, i.e. to a local development platform
+	Plaintext bool
+	// PEM bundle of the CAs trusted to sign the platform's certificate, in place of the system roots
+	CAFile string
+	// PEM client certificate and key presented for mTLS
+	CertFile string
+	KeyFile  string
+	// overrides the server name used to verify the platform's certificate
+	ServerName string
+	// skip verification of the platform's certificate (local development only)
+	InsecureSkipVerify bool
+}
+
+// Load builds the crypto/tls config, or returns nil when the connection is plaintext
+func (c TLSConfig) Load() (*tls.Config, error) {
+	if c.Plaintext {
+		if c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.ServerName != "" || c.InsecureSkipVerify {
+			return nil, errors.New("TLS settings cannot be combined with a plaintext connection")
+		}
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // explicitly requested for local development
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("both a client certificate and key are required for mTLS")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
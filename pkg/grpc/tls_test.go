@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate and its key as PEM files
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "platform.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSConfigLoad(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config TLSConfig
+		// the expected error, where empty is success
+		err   string
+		check func(*testing.T, *tls.Config)
+	}{
+		{
+			name: "TLS with the system roots by default",
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg == nil || cfg.RootCAs != nil || len(cfg.Certificates) != 0 || cfg.InsecureSkipVerify {
+					t.Errorf("expected TLS verified against the system roots, got %+v", cfg)
+				}
+				if cfg != nil && cfg.MinVersion != tls.VersionTLS12 {
+					t.Errorf("expected TLS 1.2 or later, got minimum version %x", cfg.MinVersion)
+				}
+			},
+		},
+		{
+			name:   "plaintext",
+			config: TLSConfig{Plaintext: true},
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg != nil {
+					t.Errorf("expected no TLS config for a plaintext connection, got %+v", cfg)
+				}
+			},
+		},
+		{
+			name:   "plaintext with a CA",
+			config: TLSConfig{Plaintext: true, CAFile: certFile},
+			err:    "cannot be combined with a plaintext connection",
+		},
+		{
+			name:   "plaintext skipping verification",
+			config: TLSConfig{Plaintext: true, InsecureSkipVerify: true},
+			err:    "cannot be combined with a plaintext connection",
+		},
+		{
+			name:   "CA bundle",
+			config: TLSConfig{CAFile: certFile},
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg.RootCAs == nil {
+					t.Error("expected the CA bundle as the root CAs")
+				}
+			},
+		},
+		{
+			name:   "missing CA file",
+			config: TLSConfig{CAFile: filepath.Join(dir, "missing.pem")},
+			err:    "failed to read CA bundle",
+		},
+		{
+			name:   "CA file without certificates",
+			config: TLSConfig{CAFile: notPEM},
+			err:    "no certificates found in CA bundle",
+		},
+		{
+			name:   "client certificate and key",
+			config: TLSConfig{CertFile: certFile, KeyFile: keyFile},
+			check: func(t *testing.T, cfg *tls.Config) {
+				if len(cfg.Certificates) != 1 {
+					t.Errorf("expected the client certificate, got %d certificates", len(cfg.Certificates))
+				}
+			},
+		},
+		{
+			name:   "client certificate without its key",
+			config: TLSConfig{CertFile: certFile},
+			err:    "both a client certificate and key are required",
+		},
+		{
+			name:   "client key without its certificate",
+			config: TLSConfig{KeyFile: keyFile},
+			err:    "both a client certificate and key are required",
+		},
+		{
+			name:   "client certificate with a mismatched key file",
+			config: TLSConfig{CertFile: certFile, KeyFile: certFile},
+			err:    "failed to load client certificate",
+		},
+		{
+			name:   "server name override",
+			config: TLSConfig{ServerName: "platform.internal"},
+			check: func(t *testing.T, cfg *tls.Config) {
+				if cfg.ServerName != "platform.internal" {
+					t.Errorf("expected server name platform.internal, got %q", cfg.ServerName)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.config.Load()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
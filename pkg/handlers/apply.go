@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+const (
+	ApplyResultCreated     = "created"
+	ApplyResultUpdated     = "updated"
+	ApplyResultDeactivated = "deactivated"
+	ApplyResultUnchanged   = "unchanged"
+)
+
+// ApplyResult is the outcome of reconciling a single manifest object against the platform
+type ApplyResult struct {
+	Kind   string `json:"kind"`
+	Object string `json:"object"`
+	Result string `json:"result"`
+	Id     string `json:"id,omitempty"`
+}
+
+// applier holds the known platform state while a manifest is reconciled, keyed by the same
+// FQNs, names and URIs the manifest uses to reference objects
+type applier struct {
+	h       Handler
+	results []ApplyResult
+
+	namespaces map[string]*policy.Namespace
+	attributes map[string]*policy.Attribute
+	values     map[string]*policy.Value
+	scs        map[string]string
+	kas        map[string]*kasregistry.KeyAccessServer
+}
+
+// ApplyManifest reconciles every object in the manifest against the platform, creating, updating or
+// deactivating as needed. Results are returned for every object reconciled before any error occurred.
+func (h Handler) ApplyManifest(m *manifest.Manifest) ([]ApplyResult, error) {
+	a := &applier{
+		h:          h,
+		namespaces: map[string]*policy.Namespace{},
+		attributes: map[string]*policy.Attribute{},
+		values:     map[string]*policy.Value{},
+		scs:        map[string]string{},
+		kas:        map[string]*kasregistry.KeyAccessServer{},
+	}
+
+	steps := []func(*manifest.Manifest) error{
+		a.loadState,
+		a.applyNamespaces,
+		a.applyAttributes,
+		a.applyKasRegistry,
+		a.applySubjectConditionSets,
+		a.applySubjectMappings,
+		a.applyResourceMappings,
+		a.applyKasGrants,
+	}
+	for _, step := range steps {
+		if err := step(m); err != nil {
+			return a.results, err
+		}
+	}
+	return a.results, nil
+}
+
+func (a *applier) record(kind, object, id, result string) {
+	a.results = append(a.results, ApplyResult{Kind: kind, Object: object, Result: result, Id: id})
+}
+
+func (a *applier) loadState(_ *manifest.Manifest) error {
+	nsList, err := a.h.ListNamespaces(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for _, ns := range nsList {
+		a.namespaces[strings.ToLower(ns.GetName())] = ns
+	}
+
+	attrs, err := a.h.ListAttributes(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return fmt.Errorf("failed to list attributes: %w", err)
+	}
+	for _, attr := range attrs {
+		ns := attr.GetNamespace().GetName()
+		a.attributes[strings.ToLower(GetAttributeFqn(ns, attr.GetName()))] = attr
+		for _, v := range attr.GetValues() {
+			a.values[strings.ToLower(GetAttributeValueFqn(ns, attr.GetName(), v.GetValue()))] = v
+		}
+	}
+
+	kasList, err := a.h.ListKasRegistryEntries()
+	if err != nil {
+		return fmt.Errorf("failed to list KAS registry entries: %w", err)
+	}
+	for _, kas := range kasList {
+		a.kas[kas.GetUri()] = kas
+	}
+	return nil
+}
+
+// Returns true when the object should be deactivated. Reactivation is not supported by the platform.
+func wantsDeactivation(kind, object string, desired *bool, active bool) (bool, error) {
+	if desired == nil {
+		return false, nil
+	}
+	if *desired && !active {
+		return false, fmt.Errorf("%s %s is inactive and cannot be reactivated", kind, object)
+	}
+	return !*desired && active, nil
+}
+
+// Labels are only managed when the manifest specifies them, in which case they replace the existing set
+func labelsChanged(desired map[string]string, current *common.Metadata) bool {
+	return desired != nil && !manifest.LabelsEqual(desired, current.GetLabels())
+}
+
+func (a *applier) applyNamespaces(m *manifest.Manifest) error {
+	for _, ns := range m.Namespaces {
+		key := strings.ToLower(ns.Name)
+		result := ApplyResultUnchanged
+		existing, ok := a.namespaces[key]
+		if !ok {
+			created, err := a.h.CreateNamespace(ns.Name, labelsMetadata(ns.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create namespace %s: %w", ns.Name, err)
+			}
+			existing = created
+			result = ApplyResultCreated
+		} else if labelsChanged(ns.Labels, existing.GetMetadata()) {
+			updated, err := a.h.UpdateNamespace(existing.GetId(), labelsMetadata(ns.Labels), common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE)
+			if err != nil {
+				return fmt.Errorf("failed to update namespace %s: %w", ns.Name, err)
+			}
+			existing = updated
+			result = ApplyResultUpdated
+		}
+
+		deactivate, err := wantsDeactivation(manifest.KindNamespace, ns.Name, ns.Active, existing.GetActive().GetValue())
+		if err != nil {
+			return err
+		}
+		if deactivate {
+			if existing, err = a.h.DeactivateNamespace(existing.GetId()); err != nil {
+				return fmt.Errorf("failed to deactivate namespace %s: %w", ns.Name, err)
+			}
+			result = ApplyResultDeactivated
+		}
+
+		a.namespaces[key] = existing
+		a.record(manifest.KindNamespace, ns.Name, existing.GetId(), result)
+	}
+	return nil
+}
+
+func (a *applier) applyAttributes(m *manifest.Manifest) error {
+	for _, attr := range m.Attributes {
+		fqn := GetAttributeFqn(attr.Namespace, attr.Name)
+		ns, ok := a.namespaces[strings.ToLower(attr.Namespace)]
+		if !ok {
+			return fmt.Errorf("attribute %s: namespace %s does not exist", fqn, attr.Namespace)
+		}
+
+		rule := strings.ToUpper(attr.Rule)
+		result := ApplyResultUnchanged
+		existing, ok := a.attributes[strings.ToLower(fqn)]
+		if !ok {
+			values := make([]string, len(attr.Values))
+			for i, v := range attr.Values {
+				values[i] = v.Value
+			}
+			created, err := a.h.CreateAttribute(attr.Name, rule, ns.GetId(), values, labelsMetadata(attr.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create attribute %s: %w", fqn, err)
+			}
+			existing = created
+			result = ApplyResultCreated
+		} else {
+			if current := GetAttributeRuleFromAttributeType(existing.GetRule()); current != rule {
+				return fmt.Errorf("attribute %s: rule cannot be changed from %s to %s", fqn, current, rule)
+			}
+			if existing.GetRule() == policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY {
+				if err := hierarchyOrderReachable(attr, existing.GetValues()); err != nil {
+					return err
+				}
+			}
+			if labelsChanged(attr.Labels, existing.GetMetadata()) {
+				updated, err := a.h.UpdateAttribute(existing.GetId(), labelsMetadata(attr.Labels), common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE)
+				if err != nil {
+					return fmt.Errorf("failed to update attribute %s: %w", fqn, err)
+				}
+				existing = updated
+				result = ApplyResultUpdated
+			}
+		}
+
+		deactivate, err := wantsDeactivation(manifest.KindAttribute, fqn, attr.Active, existing.GetActive().GetValue())
+		if err != nil {
+			return err
+		}
+		if deactivate {
+			if existing, err = a.h.DeactivateAttribute(existing.GetId()); err != nil {
+				return fmt.Errorf("failed to deactivate attribute %s: %w", fqn, err)
+			}
+			result = ApplyResultDeactivated
+		}
+
+		a.attributes[strings.ToLower(fqn)] = existing
+		a.record(manifest.KindAttribute, fqn, existing.GetId(), result)
+
+		if err := a.applyValues(attr, existing, result == ApplyResultCreated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyValues(attr manifest.Attribute, parent *policy.Attribute, parentCreated bool) error {
+	current, err := a.h.ListAttributeValues(parent.GetId(), common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return fmt.Errorf("failed to list values of attribute %s: %w", GetAttributeFqn(attr.Namespace, attr.Name), err)
+	}
+	byValue := map[string]*policy.Value{}
+	for _, v := range current {
+		byValue[strings.ToLower(v.GetValue())] = v
+		a.values[strings.ToLower(GetAttributeValueFqn(attr.Namespace, attr.Name, v.GetValue()))] = v
+	}
+
+	for _, v := range attr.Values {
+		fqn := GetAttributeValueFqn(attr.Namespace, attr.Name, v.Value)
+		result := ApplyResultUnchanged
+		// values created along with their attribute still need their labels and state reconciled
+		if parentCreated {
+			result = ApplyResultCreated
+		}
+
+		existing, ok := byValue[strings.ToLower(v.Value)]
+		if !ok {
+			created, err := a.h.CreateAttributeValue(parent.GetId(), v.Value, labelsMetadata(v.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create attribute value %s: %w", fqn, err)
+			}
+			existing = created
+			result = ApplyResultCreated
+		} else if labelsChanged(v.Labels, existing.GetMetadata()) {
+			updated, err := a.h.UpdateAttributeValue(existing.GetId(), nil, labelsMetadata(v.Labels), common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE)
+			if err != nil {
+				return fmt.Errorf("failed to update attribute value %s: %w", fqn, err)
+			}
+			existing = updated
+			if result != ApplyResultCreated {
+				result = ApplyResultUpdated
+			}
+		}
+
+		deactivate, err := wantsDeactivation(manifest.KindAttributeValue, fqn, v.Active, existing.GetActive().GetValue())
+		if err != nil {
+			return err
+		}
+		if deactivate {
+			if existing, err = a.h.DeactivateAttributeValue(existing.GetId()); err != nil {
+				return fmt.Errorf("failed to deactivate attribute value %s: %w", fqn, err)
+			}
+			result = ApplyResultDeactivated
+		}
+
+		a.values[strings.ToLower(fqn)] = existing
+		a.record(manifest.KindAttributeValue, fqn, existing.GetId(), result)
+	}
+	return nil
+}
+
+// The platform cannot reorder the values of an attribute, so new values are appended after the existing ones.
+// A hierarchy ranks its values by that order, so the manifest order must still hold once they are appended.
+func hierarchyOrderReachable(attr manifest.Attribute, current []*policy.Value) error {
+	order := []string{}
+	existing := map[string]bool{}
+	for _, v := range current {
+		order = append(order, v.GetValue())
+		existing[strings.ToLower(v.GetValue())] = true
+	}
+	desired := []string{}
+	for _, v := range attr.Values {
+		desired = append(desired, v.Value)
+		if !existing[strings.ToLower(v.Value)] {
+			order = append(order, v.Value)
+		}
+	}
+
+	// the desired values must appear in the resulting order, though other existing values may sit between them
+	i := 0
+	for _, v := range order {
+		if i < len(desired) && strings.EqualFold(v, desired[i]) {
+			i++
+		}
+	}
+	if i < len(desired) {
+		return fmt.Errorf("attribute %s: hierarchy values cannot be reordered from [%s] to [%s]",
+			GetAttributeFqn(attr.Namespace, attr.Name), strings.Join(order, ", "), strings.Join(desired, ", "))
+	}
+	return nil
+}
+
+func kasPublicKeyFromManifest(key manifest.PublicKey) *kasregistry.PublicKey {
+	if key.Remote != "" {
+		return &kasregistry.PublicKey{PublicKey: &kasregistry.PublicKey_Remote{Remote: key.Remote}}
+	}
+	return &kasregistry.PublicKey{PublicKey: &kasregistry.PublicKey_Local{Local: key.Local}}
+}
+
+func (a *applier) applyKasRegistry(m *manifest.Manifest) error {
+	for _, kas := range m.KasRegistry {
+		result := ApplyResultUnchanged
+		pubKey := kasPublicKeyFromManifest(kas.PublicKey)
+		existing, ok := a.kas[kas.Uri]
+		if !ok {
+			created, err := a.h.CreateKasRegistryEntry(kas.Uri, pubKey, labelsMetadata(kas.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create KAS registry entry %s: %w", kas.Uri, err)
+			}
+			existing = created
+			result = ApplyResultCreated
+		} else {
+			keyChanged := existing.GetPublicKey().GetLocal() != kas.PublicKey.Local || existing.GetPublicKey().GetRemote() != kas.PublicKey.Remote
+			if keyChanged || labelsChanged(kas.Labels, existing.GetMetadata()) {
+				if !keyChanged {
+					pubKey = nil
+				}
+				updated, err := a.h.UpdateKasRegistryEntry(existing.GetId(), "", pubKey, labelsMetadata(kas.Labels), labelsUpdateBehavior(kas.Labels))
+				if err != nil {
+					return fmt.Errorf("failed to update KAS registry entry %s: %w", kas.Uri, err)
+				}
+				existing = updated
+				result = ApplyResultUpdated
+			}
+		}
+
+		a.kas[kas.Uri] = existing
+		a.record(manifest.KindKasRegistryEntry, kas.Uri, existing.GetId(), result)
+	}
+	return nil
+}
+
+func labelsUpdateBehavior(labels map[string]string) common.MetadataUpdateEnum {
+	if labels == nil {
+		return common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_EXTEND
+	}
+	return common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE
+}
+
+func (a *applier) applySubjectConditionSets(m *manifest.Manifest) error {
+	if len(m.SubjectConditionSets) == 0 {
+		return nil
+	}
+	list, err := a.h.ListSubjectConditionSets()
+	if err != nil {
+		return fmt.Errorf("failed to list subject condition sets: %w", err)
+	}
+
+	for _, scs := range m.SubjectConditionSets {
+		ss, err := SubjectSetsFromManifest(scs.SubjectSets)
+		if err != nil {
+			return fmt.Errorf("subject condition set %s: %w", scs.Name, err)
+		}
+		desired := manifest.SubjectSetsKey(SubjectSetsToManifest(ss))
+
+		var existing *policy.SubjectConditionSet
+		for _, s := range list {
+			if manifest.SubjectSetsKey(SubjectSetsToManifest(s.GetSubjectSets())) == desired {
+				existing = s
+				break
+			}
+		}
+
+		result := ApplyResultUnchanged
+		if existing == nil {
+			created, err := a.h.CreateSubjectConditionSet(ss, labelsMetadata(scs.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create subject condition set %s: %w", scs.Name, err)
+			}
+			existing = created
+			list = append(list, created)
+			result = ApplyResultCreated
+		} else if labelsChanged(scs.Labels, existing.GetMetadata()) {
+			updated, err := a.h.UpdateSubjectConditionSet(existing.GetId(), nil, labelsMetadata(scs.Labels), common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE)
+			if err != nil {
+				return fmt.Errorf("failed to update subject condition set %s: %w", scs.Name, err)
+			}
+			existing = updated
+			result = ApplyResultUpdated
+		}
+
+		a.scs[scs.Name] = existing.GetId()
+		a.record(manifest.KindSubjectConditionSet, scs.Name, existing.GetId(), result)
+	}
+	return nil
+}
+
+func (a *applier) value(fqn string) (*policy.Value, error) {
+	v, ok := a.values[strings.ToLower(fqn)]
+	if !ok {
+		return nil, fmt.Errorf("attribute value %s does not exist", fqn)
+	}
+	return v, nil
+}
+
+func (a *applier) applySubjectMappings(m *manifest.Manifest) error {
+	if len(m.SubjectMappings) == 0 {
+		return nil
+	}
+	list, err := a.h.ListSubjectMappings()
+	if err != nil {
+		return fmt.Errorf("failed to list subject mappings: %w", err)
+	}
+	claimed := map[string]bool{}
+
+	for _, sm := range m.SubjectMappings {
+		object := fmt.Sprintf("%s <- %s", sm.AttributeValue, sm.SubjectConditionSet)
+		value, err := a.value(sm.AttributeValue)
+		if err != nil {
+			return fmt.Errorf("subject mapping %s: %w", object, err)
+		}
+		for _, std := range sm.Actions.Standard {
+			if GetSubjectMappingActionEnumFromChoice(std) == policy.Action_STANDARD_ACTION_UNSPECIFIED {
+				return fmt.Errorf("subject mapping %s: invalid standard action %q", object, std)
+			}
+		}
+		scsId := a.scs[sm.SubjectConditionSet]
+		actions := GetFullActionsList(sm.Actions.Standard, sm.Actions.Custom)
+
+		var existing *policy.SubjectMapping
+		for _, s := range list {
+			if !claimed[s.GetId()] && s.GetAttributeValue().GetId() == value.GetId() && s.GetSubjectConditionSet().GetId() == scsId {
+				existing = s
+				break
+			}
+		}
+
+		result := ApplyResultUnchanged
+		if existing == nil {
+			created, err := a.h.CreateNewSubjectMapping(value.GetId(), actions, scsId, nil, labelsMetadata(sm.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to create subject mapping %s: %w", object, err)
+			}
+			existing = created
+			result = ApplyResultCreated
+		} else if !actionsEqual(ActionsToManifest(existing.GetActions()), ActionsToManifest(actions)) || labelsChanged(sm.Labels, existing.GetMetadata()) {
+			updated, err := a.h.UpdateSubjectMapping(existing.GetId(), "", actions, labelsMetadata(sm.Labels), labelsUpdateBehavior(sm.Labels))
+			if err != nil {
+				return fmt.Errorf("failed to update subject mapping %s: %w", object, err)
+			}
+			existing = updated
+			result = ApplyResultUpdated
+		}
+
+		claimed[existing.GetId()] = true
+		a.record(manifest.KindSubjectMapping, object, existing.GetId(), result)
+	}
+	return nil
+}
+
+func actionsEqual(a, b manifest.Actions) bool {
+	return slices.Equal(a.Standard, b.Standard) && slices.Equal(a.Custom, b.Custom)
+}
+
+func (a *applier) applyResourceMappings(m *manifest.Manifest) error {
+	if len(m.ResourceMappings) == 0 {
+		return nil
+	}
+	list, err := a.h.ListResourceMappings()
+	if err != nil {
+		return fmt.Errorf("failed to list resource mappings: %w", err)
+	}
+	claimed := map[string]bool{}
+
+	for _, rm := range m.ResourceMappings {
+		object := fmt.Sprintf("%s [%s]", rm.AttributeValue, strings.Join(rm.Terms, ", "))
+		value, err := a.value(rm.AttributeValue)
+		if err != nil {
+			return fmt.Errorf("resource mapping %s: %w", object, err)
+		}
+
+		// prefer a mapping with identical terms, otherwise update the only mapping on the value or, when the value
+		// has several, the only one sharing a term. Anything else is ambiguous rather than a new mapping.
+		var exact, candidate *policy.ResourceMapping
+		var candidates, overlapping []*policy.ResourceMapping
+		for _, r := range list {
+			if claimed[r.GetId()] || r.GetAttributeValue().GetId() != value.GetId() {
+				continue
+			}
+			if manifest.TermsEqual(r.GetTerms(), rm.Terms) {
+				exact = r
+				break
+			}
+			candidates = append(candidates, r)
+			if termsOverlap(r.GetTerms(), rm.Terms) {
+				overlapping = append(overlapping, r)
+			}
+		}
+		if exact == nil {
+			switch {
+			case len(candidates) == 1:
+				candidate = candidates[0]
+			case len(overlapping) == 1:
+				candidate = overlapping[0]
+			case len(candidates) > 1:
+				return fmt.Errorf("resource mapping %s: ambiguous, %d existing mappings of the value have different terms and %d share a term with it",
+					object, len(candidates), len(overlapping))
+			}
+		}
+
+		result := ApplyResultUnchanged
+		existing := exact
+		switch {
+		case exact != nil && labelsChanged(rm.Labels, exact.GetMetadata()):
+			existing, err = a.h.UpdateResourceMapping(exact.GetId(), value.GetId(), rm.Terms, labelsMetadata(rm.Labels), common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE)
+			result = ApplyResultUpdated
+		case candidate != nil:
+			existing, err = a.h.UpdateResourceMapping(candidate.GetId(), value.GetId(), rm.Terms, labelsMetadata(rm.Labels), labelsUpdateBehavior(rm.Labels))
+			result = ApplyResultUpdated
+		case exact == nil:
+			existing, err = a.h.CreateResourceMapping(value.GetId(), rm.Terms, labelsMetadata(rm.Labels))
+			result = ApplyResultCreated
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply resource mapping %s: %w", object, err)
+		}
+
+		claimed[existing.GetId()] = true
+		a.record(manifest.KindResourceMapping, object, existing.GetId(), result)
+	}
+	return nil
+}
+
+// termsOverlap reports whether the mappings share a term, ignoring case
+func termsOverlap(a, b []string) bool {
+	for _, t := range a {
+		for _, u := range b {
+			if strings.EqualFold(t, u) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasKasGrant(grants []*kasregistry.KeyAccessServer, kasId string) bool {
+	for _, g := range grants {
+		if g.GetId() == kasId {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *applier) applyKasGrants(m *manifest.Manifest) error {
+	for _, g := range m.KasGrants {
+		kas, ok := a.kas[g.Kas]
+		if !ok {
+			return fmt.Errorf("kas grant: KAS %s is not registered", g.Kas)
+		}
+
+		result := ApplyResultUnchanged
+		if g.Attribute != "" {
+			object := fmt.Sprintf("%s -> %s", g.Attribute, g.Kas)
+			attr, ok := a.attributes[strings.ToLower(g.Attribute)]
+			if !ok {
+				return fmt.Errorf("kas grant %s: attribute %s does not exist", object, g.Attribute)
+			}
+			full, err := a.h.GetAttribute(attr.GetId())
+			if err != nil {
+				return fmt.Errorf("kas grant %s: %w", object, err)
+			}
+			if !hasKasGrant(full.GetGrants(), kas.GetId()) {
+				if _, err := a.h.UpdateKasGrantForAttribute(attr.GetId(), kas.GetId()); err != nil {
+					return fmt.Errorf("failed to assign kas grant %s: %w", object, err)
+				}
+				result = ApplyResultCreated
+			}
+			a.record(manifest.KindKasGrant, object, "", result)
+			continue
+		}
+
+		object := fmt.Sprintf("%s -> %s", g.Value, g.Kas)
+		value, err := a.value(g.Value)
+		if err != nil {
+			return fmt.Errorf("kas grant %s: %w", object, err)
+		}
+		full, err := a.h.GetAttributeValue(value.GetId())
+		if err != nil {
+			return fmt.Errorf("kas grant %s: %w", object, err)
+		}
+		if !hasKasGrant(full.GetGrants(), kas.GetId()) {
+			if _, err := a.h.UpdateKasGrantForValue(value.GetId(), kas.GetId()); err != nil {
+				return fmt.Errorf("failed to assign kas grant %s: %w", object, err)
+			}
+			result = ApplyResultCreated
+		}
+		a.record(manifest.KindKasGrant, object, "", result)
+	}
+	return nil
+}
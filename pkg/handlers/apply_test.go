@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+func TestHierarchyOrderReachable(t *testing.T) {
+	current := []*policy.Value{{Value: "top"}, {Value: "middle"}, {Value: "bottom"}}
+
+	tests := []struct {
+		name    string
+		desired []string
+		// the expected error, where empty is success
+		err string
+	}{
+		{name: "same order", desired: []string{"top", "middle", "bottom"}},
+		{name: "values in another case", desired: []string{"TOP", "Middle", "bottom"}},
+		{name: "subset in order", desired: []string{"top", "bottom"}},
+		{name: "new values appended", desired: []string{"top", "middle", "bottom", "lowest", "lower"}},
+		{name: "new value without existing values", desired: []string{"lowest"}},
+		{name: "swapped values", desired: []string{"middle", "top", "bottom"}, err: "cannot be reordered from [top, middle, bottom] to [middle, top, bottom]"},
+		{name: "new value before existing ones", desired: []string{"highest", "top", "middle", "bottom"}, err: "cannot be reordered from [top, middle, bottom, highest] to [highest, top, middle, bottom]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attr := manifest.Attribute{Namespace: "example.com", Name: "level", Rule: "HIERARCHY"}
+			for _, v := range tt.desired {
+				attr.Values = append(attr.Values, manifest.Value{Value: v})
+			}
+			err := hierarchyOrderReachable(attr, current)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -63,6 +63,9 @@ func (h Handler) CreateAttribute(name string, rule string, namespace string, val
 		Metadata:    metadata,
 		Values:      values,
 	}
+	if h.DryRun {
+		return nil, dryRun("CreateAttribute", attrReq, nil, nil)
+	}
 
 	resp, err := h.sdk.Attributes.CreateAttribute(h.ctx, attrReq)
 	if err != nil {
@@ -78,11 +81,17 @@ func (h *Handler) UpdateAttribute(
 	metadata *common.MetadataMutable,
 	behavior common.MetadataUpdateEnum,
 ) (*policy.Attribute, error) {
-	_, err := h.sdk.Attributes.UpdateAttribute(h.ctx, &attributes.UpdateAttributeRequest{
+	req := &attributes.UpdateAttributeRequest{
 		Id:                     id,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttribute(id)
+		return nil, dryRun("UpdateAttribute", req, current, err)
+	}
+
+	_, err := h.sdk.Attributes.UpdateAttribute(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -91,9 +100,15 @@ func (h *Handler) UpdateAttribute(
 
 // Deactivates and returns deactivated attribute
 func (h Handler) DeactivateAttribute(id string) (*policy.Attribute, error) {
-	_, err := h.sdk.Attributes.DeactivateAttribute(h.ctx, &attributes.DeactivateAttributeRequest{
+	req := &attributes.DeactivateAttributeRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttribute(id)
+		return nil, withDependents(dryRun("DeactivateAttribute", req, current, err), h.AttributeDependents, id)
+	}
+
+	_, err := h.sdk.Attributes.DeactivateAttribute(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+
 	"github.com/opentdf/platform/protocol/go/common"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/attributes"
@@ -16,11 +18,16 @@ func (h *Handler) ListAttributeValues(attributeId string, state common.ActiveSta
 
 // Creates and returns the created value
 func (h *Handler) CreateAttributeValue(attributeId string, value string, metadata *common.MetadataMutable) (*policy.Value, error) {
-	resp, err := h.sdk.Attributes.CreateAttributeValue(h.ctx, &attributes.CreateAttributeValueRequest{
+	req := &attributes.CreateAttributeValueRequest{
 		AttributeId: attributeId,
 		Value:       value,
 		Metadata:    metadata,
-	})
+	}
+	if h.DryRun {
+		return nil, dryRun("CreateAttributeValue", req, nil, nil)
+	}
+
+	resp, err := h.sdk.Attributes.CreateAttributeValue(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +48,18 @@ func (h *Handler) GetAttributeValue(id string) (*policy.Value, error) {
 
 // Updates and returns updated value
 func (h *Handler) UpdateAttributeValue(id string, memberIds []string, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.Value, error) {
-	resp, err := h.sdk.Attributes.UpdateAttributeValue(h.ctx, &attributes.UpdateAttributeValueRequest{
+	req := &attributes.UpdateAttributeValueRequest{
 		Id:                     id,
 		Members:                memberIds,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttributeValue(id)
+		return nil, dryRun("UpdateAttributeValue", req, current, err)
+	}
+
+	resp, err := h.sdk.Attributes.UpdateAttributeValue(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -56,11 +69,21 @@ func (h *Handler) UpdateAttributeValue(id string, memberIds []string, metadata *
 
 // Deactivates and returns deactivated value
 func (h *Handler) DeactivateAttributeValue(id string) (*policy.Value, error) {
-	_, err := h.sdk.Attributes.DeactivateAttributeValue(h.ctx, &attributes.DeactivateAttributeValueRequest{
+	req := &attributes.DeactivateAttributeValueRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttributeValue(id)
+		return nil, withDependents(dryRun("DeactivateAttributeValue", req, current, err), h.AttributeValueDependents, id)
+	}
+
+	_, err := h.sdk.Attributes.DeactivateAttributeValue(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	return h.GetAttributeValue(id)
 }
+
+func GetAttributeValueFqn(namespace string, attribute string, value string) string {
+	return fmt.Sprintf("%s/value/%s", GetAttributeFqn(namespace, attribute), value)
+}
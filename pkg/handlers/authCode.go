@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const OTDFCTL_REFRESH_TOKEN_KEY = "OTDFCTL_REFRESH_TOKEN"
+
+// how long to wait for the user to complete the login in the browser
+const authorizationCodeTimeout = 5 * time.Minute
+
+// AuthorizationCodeOptions configures the Authorization Code + PKCE login
+type AuthorizationCodeOptions struct {
+	ClientID string
+	// IdP endpoints given explicitly, where missing ones are discovered
+	IdP    OIDCConfiguration
+	Scopes []string
+	// port of the loopback redirect listener, where 0 picks a free port
+	Port int
+	// OpenURL sends the user to the authorize URL, i.e. by opening a browser
+	OpenURL func(url string) error
+}
+
+type authorizationCodeResult struct {
+	code string
+	err  error
+}
+
+// GetTokenWithAuthorizationCode runs the OAuth2 Authorization Code flow with PKCE for a public client. The IdP
+// redirects back to a loopback listener, and the code is exchanged for access and refresh tokens, which are
+// cached in the keyring.
+func (h *Handler) GetTokenWithAuthorizationCode(opts AuthorizationCodeOptions) (*oauth2.Token, error) {
+	idp, err := h.resolveOIDCConfiguration(opts.IdP, func(c OIDCConfiguration) bool {
+		return c.AuthorizationEndpoint != "" && c.TokenEndpoint != ""
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", opts.Port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start loopback listener: %w", err)
+	}
+	defer listener.Close()
+
+	config := oauth2.Config{
+		ClientID:    opts.ClientID,
+		Endpoint:    oauth2.Endpoint{AuthURL: idp.AuthorizationEndpoint, TokenURL: idp.TokenEndpoint, AuthStyle: oauth2.AuthStyleInParams},
+		RedirectURL: fmt.Sprintf("http://%s/callback", listener.Addr().String()),
+		Scopes:      opts.Scopes,
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	results := make(chan authorizationCodeResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var result authorizationCodeResult
+		switch {
+		case q.Get("state") != state:
+			result.err = errors.New("authorization response state does not match the request")
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			result.err = errors.New("authorization response is missing the code")
+		default:
+			result.code = q.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login complete. You may close this window and return to otdfctl.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener) //nolint:errcheck // returns once the server is closed
+	defer server.Close()
+
+	authURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	if err := opts.OpenURL(authURL); err != nil {
+		return nil, fmt.Errorf("failed to open the authorize URL: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(h.ctx, authorizationCodeTimeout)
+	defer cancel()
+
+	var result authorizationCodeResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, errors.New("timed out waiting for the login to complete in the browser")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := config.Exchange(ctx, result.code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+
+	if err := cacheUserTokens(h.loginCache(idp.Issuer), idp.TokenEndpoint, opts.ClientID, token); err != nil {
+		return nil, err
+	}
+	h.OIDC_TOKEN = token.AccessToken
+	return token, nil
+}
+
+// refreshCachedToken uses the cached refresh token of an interactive login to obtain a new access token
+func refreshCachedToken(ctx context.Context, login loginCache) (*oauth2.Token, error) {
+	refreshToken, err := login.get(OTDFCTL_REFRESH_TOKEN_KEY)
+	if err != nil {
+		return nil, err
+	}
+	clientId, err := login.get(OTDFCTL_CLIENT_ID_CACHE_KEY)
+	if err != nil {
+		return nil, err
+	}
+	// cached with the login, so refreshing needs no discovery
+	tokenURL, err := login.get(OTDFCTL_TOKEN_ENDPOINT_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	config := oauth2.Config{
+		ClientID: clientId,
+		Endpoint: oauth2.Endpoint{TokenURL: tokenURL, AuthStyle: oauth2.AuthStyleInParams},
+	}
+	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := cacheUserTokens(login, tokenURL, clientId, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// cacheUserTokens stores the client id, token endpoint, access token and refresh token of an interactive login in
+// the keyring, and makes it the platform's current login
+func cacheUserTokens(login loginCache, tokenURL, clientID string, token *oauth2.Token) error {
+	if err := login.set(OTDFCTL_CLIENT_ID_CACHE_KEY, clientID); err != nil {
+		return fmt.Errorf("failed to store client ID in keyring: %v", err)
+	}
+	if err := login.set(OTDFCTL_TOKEN_ENDPOINT_KEY, tokenURL); err != nil {
+		return fmt.Errorf("failed to store token endpoint in keyring: %v", err)
+	}
+	if err := login.set(OTDFCTL_OIDC_TOKEN_KEY, token.AccessToken); err != nil {
+		return fmt.Errorf("failed to store OIDC Token in keyring: %v", err)
+	}
+	if token.RefreshToken != "" {
+		if err := login.set(OTDFCTL_REFRESH_TOKEN_KEY, token.RefreshToken); err != nil {
+			return fmt.Errorf("failed to store refresh token in keyring: %v", err)
+		}
+	} else if err := login.delete(OTDFCTL_REFRESH_TOKEN_KEY); err != nil {
+		// a refresh token of an earlier login would renew the wrong token
+		return fmt.Errorf("failed to clear refresh token from keyring: %v", err)
+	}
+	if err := login.makeCurrent(); err != nil {
+		return fmt.Errorf("failed to store issuer in keyring: %v", err)
+	}
+	return nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/credstore"
+)
+
+const (
+	testClientID = "otdfctl-test"
+	testCode     = "test-code"
+	testPlatform = "platform.example.com"
+)
+
+// mockIdP serves the authorize and token endpoints of an OIDC provider, redirecting every authorization back with
+// a code and checking the PKCE verifier of the exchange against the challenge of the authorization
+type mockIdP struct {
+	// query parameters the authorize endpoint adds to the redirect, replacing the code and state
+	redirect url.Values
+	// the token endpoint omits the refresh token
+	noRefreshToken bool
+
+	mu          sync.Mutex
+	challenge   string
+	redirectURI string
+}
+
+func (m *mockIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != testClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" ||
+		q.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request "+r.URL.RawQuery, http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	m.challenge = q.Get("code_challenge")
+	m.redirectURI = q.Get("redirect_uri")
+	m.mu.Unlock()
+
+	params := url.Values{"code": {testCode}, "state": {q.Get("state")}}
+	for k, v := range m.redirect {
+		params[k] = v
+	}
+	http.Redirect(w, r, q.Get("redirect_uri")+"?"+params.Encode(), http.StatusFound)
+}
+
+func (m *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	challenge, redirectURI := m.challenge, m.redirectURI
+	m.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code", r.PostForm.Get("code") != testCode,
+		r.PostForm.Get("client_id") != testClientID, r.PostForm.Get("redirect_uri") != redirectURI:
+		tokenError(w, "invalid_grant")
+		return
+	case base64.RawURLEncoding.EncodeToString(verifier[:]) != challenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	token := map[string]interface{}{"access_token": "access-token", "token_type": "Bearer", "expires_in": 300}
+	if !m.noRefreshToken {
+		token["refresh_token"] = "refresh-token"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(token) //nolint:errcheck // the test fails on a missing token
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code}) //nolint:errcheck // the test fails on the error
+}
+
+func TestGetTokenWithAuthorizationCode(t *testing.T) {
+	tests := []struct {
+		name string
+		// query parameters of the redirect and whether the refresh token is omitted, see mockIdP
+		redirect       url.Values
+		noRefreshToken bool
+		openURL        func(authURL string) string
+		// the expected error, where empty is success
+		err string
+	}{
+		{
+			name: "exchanges the code and stores the tokens",
+		},
+		{
+			name:           "clears the refresh token of an earlier login",
+			noRefreshToken: true,
+		},
+		{
+			name:     "rejects a redirect with another state",
+			redirect: url.Values{"state": {"forged"}},
+			err:      "state does not match",
+		},
+		{
+			name:     "reports an authorization error",
+			redirect: url.Values{"code": {""}, "error": {"access_denied"}, "error_description": {"denied"}},
+			err:      "authorization failed: access_denied denied",
+		},
+		{
+			name:     "reports an exchange the IdP rejects",
+			redirect: url.Values{"code": {"another-code"}},
+			err:      "failed to exchange the authorization code",
+		},
+		{
+			name: "fails the exchange when the challenge does not match the verifier",
+			openURL: func(authURL string) string {
+				return replaceQuery(authURL, "code_challenge", base64.RawURLEncoding.EncodeToString(make([]byte, 32)))
+			},
+			err: "failed to exchange the authorization code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := credstore.NewMemory(nil)
+			UseCredentialStore(store)
+			t.Cleanup(func() { UseCredentialStore(credstore.NewKeyring()) })
+
+			mux := http.NewServeMux()
+			idp := &mockIdP{redirect: tt.redirect, noRefreshToken: tt.noRefreshToken}
+			mux.HandleFunc("/authorize", idp.authorize)
+			mux.HandleFunc("/token", idp.token)
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			tokenURL := server.URL + "/token"
+
+			login := loginCache{platform: testPlatform, issuer: server.URL}
+			if err := login.set(OTDFCTL_REFRESH_TOKEN_KEY, "stale-refresh-token"); err != nil {
+				t.Fatal(err)
+			}
+
+			h := &Handler{ctx: context.Background(), platformEndpoint: testPlatform}
+			token, err := h.GetTokenWithAuthorizationCode(AuthorizationCodeOptions{
+				ClientID: testClientID,
+				IdP:      OIDCConfiguration{Issuer: server.URL, AuthorizationEndpoint: server.URL + "/authorize", TokenEndpoint: tokenURL},
+				Scopes:   []string{"openid"},
+				// the browser follows the redirect of the authorize endpoint to the loopback listener
+				OpenURL: func(authURL string) error {
+					if tt.openURL != nil {
+						authURL = tt.openURL(authURL)
+					}
+					resp, err := http.Get(authURL) //nolint:gosec,noctx // the URL of the test server
+					if err != nil {
+						return err
+					}
+					return resp.Body.Close()
+				},
+			})
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				if _, err := credentials.Get(platformService(testPlatform), OTDFCTL_ISSUER_KEY); err == nil {
+					t.Error("a failed login must not become the current login")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != "access-token" || h.OIDC_TOKEN != "access-token" {
+				t.Errorf("expected the exchanged access token, got %q and %q", token.AccessToken, h.OIDC_TOKEN)
+			}
+
+			current, err := currentLogin(testPlatform)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current != login {
+				t.Errorf("expected current login %v, got %v", login, current)
+			}
+			wantRefreshToken := "refresh-token"
+			if tt.noRefreshToken {
+				wantRefreshToken = ""
+			}
+			for key, want := range map[string]string{
+				OTDFCTL_CLIENT_ID_CACHE_KEY: testClientID,
+				OTDFCTL_TOKEN_ENDPOINT_KEY:  tokenURL,
+				OTDFCTL_OIDC_TOKEN_KEY:      "access-token",
+				OTDFCTL_REFRESH_TOKEN_KEY:   wantRefreshToken,
+			} {
+				got, err := login.get(key)
+				if want == "" {
+					if err == nil {
+						t.Errorf("expected no %s, got %q", key, got)
+					}
+					continue
+				}
+				if err != nil || got != want {
+					t.Errorf("expected %s %q, got %q (%v)", key, want, got, err)
+				}
+			}
+		})
+	}
+}
+
+func replaceQuery(rawURL, key, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+// DeviceCodeOptions configures the Device Authorization Grant login
+type DeviceCodeOptions struct {
+	ClientID string
+	// IdP endpoints given explicitly, where missing ones are discovered
+	IdP    OIDCConfiguration
+	Scopes []string
+	// Prompt shows the user where to enter the user code before the token endpoint is polled
+	Prompt func(*oauth2.DeviceAuthResponse)
+}
+
+// GetTokenWithDeviceCode runs the OAuth2 Device Authorization Grant (RFC 8628) for a public client on a machine
+// without a browser. The user completes the login on another device while the token endpoint is polled, and the
+// access and refresh tokens are cached in the keyring.
+func (h *Handler) GetTokenWithDeviceCode(opts DeviceCodeOptions) (*oauth2.Token, error) {
+	idp, err := h.resolveOIDCConfiguration(opts.IdP, func(c OIDCConfiguration) bool {
+		return c.DeviceAuthorizationEndpoint != "" && c.TokenEndpoint != ""
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	config := oauth2.Config{
+		ClientID: opts.ClientID,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: idp.DeviceAuthorizationEndpoint,
+			TokenURL:      idp.TokenEndpoint,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		Scopes: opts.Scopes,
+	}
+
+	resp, err := config.DeviceAuth(h.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+	opts.Prompt(resp)
+
+	// polls at the interval given by the IdP until the user completes the login or the device code expires
+	token, err := config.DeviceAccessToken(h.ctx, resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain a token with the device code: %w", err)
+	}
+
+	if err := cacheUserTokens(h.loginCache(idp.Issuer), idp.TokenEndpoint, opts.ClientID, token); err != nil {
+		return nil, err
+	}
+	h.OIDC_TOKEN = token.AccessToken
+	return token, nil
+}
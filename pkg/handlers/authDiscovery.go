@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	platformWellKnownPath = "/.well-known/opentdf-configuration"
+	oidcWellKnownPath     = "/.well-known/openid-configuration"
+)
+
+// OIDCConfiguration holds the IdP endpoints used to log in, as found in an OIDC discovery document
+type OIDCConfiguration struct {
+	Issuer                      string   `json:"issuer"`
+	AuthorizationEndpoint       string   `json:"authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	ScopesSupported             []string `json:"scopes_supported,omitempty"`
+}
+
+// the platform publishes the discovery document of its IdP within its own well-known configuration
+type platformWellKnownConfiguration struct {
+	Configuration struct {
+		Idp            OIDCConfiguration `json:"idp"`
+		PlatformIssuer string            `json:"platform_issuer"`
+	} `json:"configuration"`
+}
+
+// DiscoverOIDCConfiguration resolves the IdP endpoints from the OIDC discovery document of the given issuer, or
+// when no issuer is given, from the platform's well-known configuration
+func (h Handler) DiscoverOIDCConfiguration(issuer string) (*OIDCConfiguration, error) {
+	if issuer == "" {
+		client, err := h.platformHTTPClient()
+		if err != nil {
+			return nil, err
+		}
+		scheme := "https"
+		if h.tlsConfig.Plaintext {
+			scheme = "http"
+		}
+		platform := platformWellKnownConfiguration{}
+		if err := getJSON(h.ctx, client, scheme+"://"+h.platformEndpoint+platformWellKnownPath, &platform); err != nil {
+			return nil, fmt.Errorf("failed to discover the platform's IdP: %w", err)
+		}
+		idp := platform.Configuration.Idp
+		if idp.TokenEndpoint != "" {
+			if idp.Issuer == "" {
+				idp.Issuer = platform.Configuration.PlatformIssuer
+			}
+			return &idp, nil
+		}
+		issuer = idp.Issuer
+		if issuer == "" {
+			issuer = platform.Configuration.PlatformIssuer
+		}
+		if issuer == "" {
+			return nil, errors.New("the platform's well-known configuration does not name an IdP issuer")
+		}
+	}
+
+	oidc := &OIDCConfiguration{}
+	// the IdP is reached like the token endpoint later is, with the default HTTP client
+	if err := getJSON(h.ctx, http.DefaultClient, strings.TrimSuffix(issuer, "/")+oidcWellKnownPath, oidc); err != nil {
+		return nil, fmt.Errorf("failed to discover the OIDC configuration of %s: %w", issuer, err)
+	}
+	if oidc.TokenEndpoint == "" {
+		return nil, fmt.Errorf("the OIDC configuration of %s has no token endpoint", issuer)
+	}
+	return oidc, nil
+}
+
+// resolveOIDCConfiguration fills in the endpoints missing from the given configuration by discovery, which is
+// skipped when complete reports the given endpoints suffice. Given endpoints take precedence over discovered ones.
+func (h Handler) resolveOIDCConfiguration(given OIDCConfiguration, complete func(OIDCConfiguration) bool) (OIDCConfiguration, error) {
+	if complete(given) {
+		// logins are cached per issuer, so without one the token endpoint identifies the IdP
+		if given.Issuer == "" {
+			given.Issuer = given.TokenEndpoint
+		}
+		return given, nil
+	}
+
+	discovered, err := h.DiscoverOIDCConfiguration(given.Issuer)
+	if err != nil {
+		return OIDCConfiguration{}, err
+	}
+	if given.AuthorizationEndpoint != "" {
+		discovered.AuthorizationEndpoint = given.AuthorizationEndpoint
+	}
+	if given.TokenEndpoint != "" {
+		discovered.TokenEndpoint = given.TokenEndpoint
+	}
+	if given.DeviceAuthorizationEndpoint != "" {
+		discovered.DeviceAuthorizationEndpoint = given.DeviceAuthorizationEndpoint
+	}
+	if !complete(*discovered) {
+		return OIDCConfiguration{}, fmt.Errorf("the OIDC configuration of %s lacks an endpoint needed for this login", discovered.Issuer)
+	}
+	return *discovered, nil
+}
+
+// platformHTTPClient uses the same TLS settings as the platform connection, so discovery works with private PKI
+func (h Handler) platformHTTPClient() (*http.Client, error) {
+	tlsCfg, err := h.tlsConfig.Load()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
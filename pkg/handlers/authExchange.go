@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// RFC 8693 grant and token types
+const (
+	tokenExchangeGrantType   = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType          = "urn:ietf:params:oauth:token-type:access_token"
+	tokenExchangeContentType = "application/x-www-form-urlencoded"
+)
+
+// TokenExchangeOptions configures the OAuth2 Token Exchange (RFC 8693) login
+type TokenExchangeOptions struct {
+	// the client performing the exchange, which the IdP must permit to exchange tokens; the secret is empty for
+	// a public client
+	ClientID     string
+	ClientSecret string
+	// IdP endpoints given explicitly, where missing ones are discovered
+	IdP OIDCConfiguration
+	// the token to exchange (default is the access token of the platform's cached login)
+	SubjectToken string
+	// the subject to impersonate, i.e. a service account (default is the subject of the exchanged token)
+	RequestedSubject string
+	Audience         string
+	Scopes           []string
+}
+
+type tokenExchangeResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	RefreshToken    string `json:"refresh_token"`
+	Scope           string `json:"scope"`
+}
+
+// tokenExchangeError is the OAuth2 error response of the token endpoint
+type tokenExchangeError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// GetTokenWithTokenExchange exchanges a token for one issued to the requested subject and caches it as the
+// platform's login, so the following commands act as that subject until the next login or logout
+func (h *Handler) GetTokenWithTokenExchange(opts TokenExchangeOptions) (*oauth2.Token, error) {
+	idp, err := h.resolveOIDCConfiguration(opts.IdP, func(c OIDCConfiguration) bool { return c.TokenEndpoint != "" })
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SubjectToken == "" {
+		if opts.SubjectToken, err = h.GetAccessToken(); err != nil {
+			return nil, fmt.Errorf("no token to exchange: %w", err)
+		}
+	}
+
+	token, err := exchangeToken(h.ctx, idp.TokenEndpoint, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	login := h.loginCache(idp.Issuer)
+	if err := cacheUserTokens(login, idp.TokenEndpoint, opts.ClientID, token); err != nil {
+		return nil, err
+	}
+	// without this, an expired exchanged token would be renewed with the client's own credentials rather than
+	// as the requested subject
+	if err := login.delete(opts.ClientID); err != nil {
+		return nil, fmt.Errorf("failed to clear client secret from keyring: %v", err)
+	}
+	h.OIDC_TOKEN = token.AccessToken
+	return token, nil
+}
+
+// exchangeToken performs the RFC 8693 token exchange, which the oauth2 package does not support
+func exchangeToken(ctx context.Context, tokenURL string, opts TokenExchangeOptions) (*oauth2.Token, error) {
+	form := url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"subject_token":        {opts.SubjectToken},
+		"subject_token_type":   {accessTokenType},
+		"requested_token_type": {accessTokenType},
+		"client_id":            {opts.ClientID},
+	}
+	if opts.ClientSecret != "" {
+		form.Set("client_secret", opts.ClientSecret)
+	}
+	if opts.RequestedSubject != "" {
+		form.Set("requested_subject", opts.RequestedSubject)
+	}
+	if opts.Audience != "" {
+		form.Set("audience", opts.Audience)
+	}
+	if len(opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", tokenExchangeContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e := tokenExchangeError{}
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			return nil, fmt.Errorf("failed to exchange token: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to exchange token: %s %s", e.Error, e.ErrorDescription)
+	}
+
+	r := tokenExchangeResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("failed to read token exchange response: %w", err)
+	}
+	if r.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange response has no access token")
+	}
+	token := &oauth2.Token{AccessToken: r.AccessToken, TokenType: r.TokenType, RefreshToken: r.RefreshToken}
+	if r.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
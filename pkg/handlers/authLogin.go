@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
-	"github.com/zalando/go-keyring"
+	"github.com/opentdf/otdfctl/pkg/credstore"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
@@ -13,11 +18,108 @@ import (
 const (
 	OTDFCTL_CLIENT_ID_CACHE_KEY = "OTDFCTL_DEFAULT_CLIENT_ID"
 	OTDFCTL_OIDC_TOKEN_KEY      = "OTDFCTL_OIDC_TOKEN"
+	OTDFCTL_TOKEN_ENDPOINT_KEY  = "OTDFCTL_TOKEN_ENDPOINT"
+	OTDFCTL_ISSUER_KEY          = "OTDFCTL_ISSUER"
+	// the issuers of all the platform's logins, one per line, as the keyring cannot list services
+	OTDFCTL_ISSUERS_KEY = "OTDFCTL_ISSUERS"
 )
 
-// we're hardcoding this for now, but eventually it will be retrieved from the backend config
-// TODO udpate to use the wellknown endpoint for the platform (https://github.com/opentdf/platform/pull/296)
-const TOKEN_URL = "http://localhost:8888/auth/realms/opentdf/protocol/openid-connect/token"
+// credentials stores the cached logins, in the OS keychain unless another store is configured
+var credentials credstore.Store = credstore.NewKeyring()
+
+// UseCredentialStore replaces the store cached logins are kept in, i.e. with an encrypted file where there is no
+// OS keychain
+func UseCredentialStore(store credstore.Store) {
+	credentials = store
+}
+
+// loginCache addresses the keyring entries of a login to one platform through one IdP issuer, so logins to
+// several platforms and IdPs are cached side by side
+type loginCache struct {
+	platform string
+	issuer   string
+}
+
+func (c loginCache) service() string {
+	return fmt.Sprintf("otdfctl %s %s", c.platform, c.issuer)
+}
+
+func (c loginCache) get(key string) (string, error) {
+	return credentials.Get(c.service(), key)
+}
+
+func (c loginCache) set(key, value string) error {
+	return credentials.Set(c.service(), key, value)
+}
+
+func (c loginCache) delete(key string) error {
+	if err := credentials.Delete(c.service(), key); err != nil && !errors.Is(err, credstore.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// clear deletes every keyring entry of the login
+func (c loginCache) clear() error {
+	keys := []string{OTDFCTL_OIDC_TOKEN_KEY, OTDFCTL_REFRESH_TOKEN_KEY, OTDFCTL_TOKEN_ENDPOINT_KEY}
+	// the client secret is stored under the client id
+	if clientId, err := c.get(OTDFCTL_CLIENT_ID_CACHE_KEY); err == nil && clientId != "" {
+		keys = append(keys, clientId)
+	}
+	keys = append(keys, OTDFCTL_CLIENT_ID_CACHE_KEY)
+	for _, key := range keys {
+		if err := c.delete(key); err != nil {
+			return fmt.Errorf("failed to delete %s from keyring: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// makeCurrent records the issuer as the one the platform's connections authenticate with, and among the issuers
+// the platform has logins with
+func (c loginCache) makeCurrent() error {
+	issuers, err := loginIssuers(c.platform)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(issuers, c.issuer) {
+		issuers = append(issuers, c.issuer)
+		if err := credentials.Set(platformService(c.platform), OTDFCTL_ISSUERS_KEY, strings.Join(issuers, "\n")); err != nil {
+			return err
+		}
+	}
+	return credentials.Set(platformService(c.platform), OTDFCTL_ISSUER_KEY, c.issuer)
+}
+
+// loginIssuers lists the issuers the platform has cached logins with
+func loginIssuers(platform string) ([]string, error) {
+	issuers, err := credentials.Get(platformService(platform), OTDFCTL_ISSUERS_KEY)
+	if errors.Is(err, credstore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(issuers, "\n"), nil
+}
+
+// platformService is the keyring service recording which issuers a platform has logins with and which is current
+func platformService(platform string) string {
+	return "otdfctl " + platform
+}
+
+// currentLogin addresses the keyring entries of the platform's most recent login
+func currentLogin(platform string) (loginCache, error) {
+	issuer, err := credentials.Get(platformService(platform), OTDFCTL_ISSUER_KEY)
+	if err != nil {
+		return loginCache{}, err
+	}
+	return loginCache{platform: platform, issuer: issuer}, nil
+}
+
+func (h Handler) loginCache(issuer string) loginCache {
+	return loginCache{platform: h.platformEndpoint, issuer: issuer}
+}
 
 // CheckTokenExpiration checks if an OIDC token has expired.
 // Returns true if the token is still valid, false otherwise.
@@ -39,28 +141,36 @@ func CheckTokenExpiration(tokenString string) (bool, error) {
 	return false, fmt.Errorf("expiration time (exp) claim is missing or invalid")
 }
 
-// GetOIDCTokenFromCache retrieves the OIDC token from the keyring.
-func GetOIDCTokenFromCache() (string, error) {
-	token, err := keyring.Get(TOKEN_URL, OTDFCTL_OIDC_TOKEN_KEY)
+// GetOIDCTokenFromCache retrieves the OIDC token of the platform's current login from the keyring.
+func (h Handler) GetOIDCTokenFromCache() (string, error) {
+	login, err := currentLogin(h.platformEndpoint)
 	if err != nil {
 		return "", err
 	}
-	return token, nil
+	return login.get(OTDFCTL_OIDC_TOKEN_KEY)
 }
 
-// GetClientIDFromCache retrieves the client ID from the keyring.
-func GetClientIDFromCache() (string, error) {
-	clientId, err := keyring.Get(TOKEN_URL, OTDFCTL_CLIENT_ID_CACHE_KEY)
+// GetClientIDFromCache retrieves the client ID of the platform's current login from the keyring.
+func (h Handler) GetClientIDFromCache() (string, error) {
+	login, err := currentLogin(h.platformEndpoint)
 	if err != nil {
 		return "", err
 	}
-	return clientId, nil
+	return login.get(OTDFCTL_CLIENT_ID_CACHE_KEY)
 }
 
-// GetClientSecretFromCache retrieves the client secret from the keyring.
-func GetClientIdAndSecretFromCache() (string, string, error) {
+// GetClientSecretFromCache retrieves the client secret of the platform's current login from the keyring.
+func (h Handler) GetClientIdAndSecretFromCache() (string, string, error) {
+	login, err := currentLogin(h.platformEndpoint)
+	if err != nil {
+		return "", "", err
+	}
+	return login.clientIdAndSecret()
+}
+
+func (c loginCache) clientIdAndSecret() (string, string, error) {
 	// our clientSecret key, is our clientId, so we gotta grab that first
-	clientId, err := keyring.Get(TOKEN_URL, OTDFCTL_CLIENT_ID_CACHE_KEY)
+	clientId, err := c.get(OTDFCTL_CLIENT_ID_CACHE_KEY)
 	if err != nil {
 		// we failed to get the clientId for somereason
 		return "", "", err
@@ -70,54 +180,37 @@ func GetClientIdAndSecretFromCache() (string, string, error) {
 		return "", "", fmt.Errorf("no clientId found in keyring")
 	}
 
-	clientSecret, err := keyring.Get(TOKEN_URL, clientId)
+	clientSecret, err := c.get(clientId)
 	if err != nil {
 		return "", "", err
 	}
 	return clientSecret, clientId, nil
 }
 
-// DEBUG_PrintKeyRingSecrets prints all the secrets in the keyring.
-func (h *Handler) DEBUG_PrintKeyRingSecrets() {
-	clientId, err := keyring.Get(TOKEN_URL, OTDFCTL_CLIENT_ID_CACHE_KEY)
+// GetTokenWithClientCredentials uses the OAuth2 client credentials flow to obtain a token. The token endpoint is
+// discovered unless given in idp.
+func (h *Handler) GetTokenWithClientCredentials(clientID, clientSecret string, idp OIDCConfiguration, noCache bool) (*oauth2.Token, error) {
+	idp, err := h.resolveOIDCConfiguration(idp, func(c OIDCConfiguration) bool { return c.TokenEndpoint != "" })
 	if err != nil {
-		fmt.Println("Failed to retrieve secret from keyring:", err)
-		return
-	}
-
-	// and our special clientId key, to grab the secret
-	secret, errSec := keyring.Get(TOKEN_URL, clientId)
-	OIDC_TOKEN, errToken := keyring.Get(TOKEN_URL, OTDFCTL_OIDC_TOKEN_KEY)
-
-	if errSec != nil {
-		fmt.Println("Failed to retrieve secret from keyring:", err)
-		return
+		return nil, err
 	}
 
-	if errToken != nil {
-		fmt.Println("Failed to retrieve secret from keyring:", errToken)
-		return
+	token, err := getTokenWithClientCredentials(h.ctx, h.loginCache(idp.Issuer), clientID, clientSecret, idp.TokenEndpoint, noCache)
+	if err != nil {
+		return nil, err
 	}
-
-	fmt.Println(clientId, ":", secret)
-	fmt.Println("Stored OIDC_TOKEN OF:", OIDC_TOKEN)
+	h.OIDC_TOKEN = token.AccessToken
+	return token, nil
 }
 
-// GetTokenWithClientCredentials uses the OAuth2 client credentials flow to obtain a token.
-func (h *Handler) GetTokenWithClientCredentials(clientID, clientSecret, tokenURL string, noCache bool) (*oauth2.Token, error) {
-	// did the user pass a custom tokenURL?
-	if tokenURL == "" {
-		// use the default hardcoded constant
-		tokenURL = TOKEN_URL
-	}
-
+func getTokenWithClientCredentials(ctx context.Context, login loginCache, clientID, clientSecret, tokenURL string, noCache bool) (*oauth2.Token, error) {
 	config := clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		TokenURL:     tokenURL,
 	}
 
-	token, err := config.Token(h.ctx)
+	token, err := config.Token(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -125,10 +218,10 @@ func (h *Handler) GetTokenWithClientCredentials(clientID, clientSecret, tokenURL
 	// if the users didn't specifically specify not to cache, then we'll cache the clientID, clientSecret, and OIDC_TOKEN in the keyring
 	if !noCache {
 		// lets store our id and secret in the keyring
-		errID := keyring.Set(tokenURL, OTDFCTL_CLIENT_ID_CACHE_KEY, clientID)
-		err := keyring.Set(tokenURL, clientID, clientSecret)
+		errID := login.set(OTDFCTL_CLIENT_ID_CACHE_KEY, clientID)
+		err := login.set(clientID, clientSecret)
 		// lets also store the oidc token
-		errToken := keyring.Set(tokenURL, OTDFCTL_OIDC_TOKEN_KEY, token.AccessToken)
+		errToken := login.set(OTDFCTL_OIDC_TOKEN_KEY, token.AccessToken)
 		if err != nil {
 			return nil, err
 		}
@@ -140,7 +233,42 @@ func (h *Handler) GetTokenWithClientCredentials(clientID, clientSecret, tokenURL
 		if errToken != nil {
 			return nil, fmt.Errorf("failed to store OIDC Token in keyring: %v", errToken)
 		}
+
+		if err := login.set(OTDFCTL_TOKEN_ENDPOINT_KEY, tokenURL); err != nil {
+			return nil, fmt.Errorf("failed to store token endpoint in keyring: %v", err)
+		}
+
+		// a refresh token left by an interactive login belongs to a different client
+		if err := login.delete(OTDFCTL_REFRESH_TOKEN_KEY); err != nil {
+			return nil, fmt.Errorf("failed to clear refresh token from keyring: %v", err)
+		}
+
+		if err := login.makeCurrent(); err != nil {
+			return nil, fmt.Errorf("failed to store issuer in keyring: %v", err)
+		}
 	}
-	h.OIDC_TOKEN = token.AccessToken
 	return token, nil
 }
+
+// CredentialsFromEnv supplies a client credentials login from the environment to the in-memory credential store,
+// for CI runners where no login outlives the process: OTDFCTL_CLIENT_ID, OTDFCTL_CLIENT_SECRET and
+// OTDFCTL_TOKEN_ENDPOINT, or else a ready-made OTDFCTL_ACCESS_TOKEN
+func CredentialsFromEnv() map[string]string {
+	clientId := os.Getenv("OTDFCTL_CLIENT_ID")
+	token := os.Getenv("OTDFCTL_ACCESS_TOKEN")
+	if clientId == "" && token == "" {
+		return nil
+	}
+	tokenURL := os.Getenv("OTDFCTL_TOKEN_ENDPOINT")
+	defaults := map[string]string{
+		// the memory store answers for every service, so any issuer locates the login
+		OTDFCTL_ISSUER_KEY:          "env",
+		OTDFCTL_TOKEN_ENDPOINT_KEY:  tokenURL,
+		OTDFCTL_CLIENT_ID_CACHE_KEY: clientId,
+		OTDFCTL_OIDC_TOKEN_KEY:      token,
+	}
+	if clientId != "" {
+		defaults[clientId] = os.Getenv("OTDFCTL_CLIENT_SECRET")
+	}
+	return defaults
+}
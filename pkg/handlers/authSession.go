@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/opentdf/otdfctl/pkg/credstore"
+)
+
+// ErrNotLoggedIn is returned when no login to the platform is cached
+var ErrNotLoggedIn = errors.New("not logged in; run 'auth login' first")
+
+// LoginStatus describes the platform's cached login without any of its secrets
+type LoginStatus struct {
+	Platform      string    `json:"platform"`
+	Issuer        string    `json:"issuer"`
+	TokenEndpoint string    `json:"tokenEndpoint"`
+	ClientId      string    `json:"clientId"`
+	Subject       string    `json:"subject,omitempty"`
+	Scopes        []string  `json:"scopes"`
+	Expiry        time.Time `json:"expiry"`
+	Expired       bool      `json:"expired"`
+	// an interactive login refreshes with its refresh token, a client credentials login with its cached secret
+	Refreshable bool `json:"refreshable"`
+}
+
+// GetLoginStatus reads the platform's cached login and the claims of its access token
+func (h Handler) GetLoginStatus() (*LoginStatus, error) {
+	login, err := h.currentLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &LoginStatus{Platform: h.platformEndpoint, Issuer: login.issuer}
+	status.ClientId, _ = login.get(OTDFCTL_CLIENT_ID_CACHE_KEY)
+	status.TokenEndpoint, _ = login.get(OTDFCTL_TOKEN_ENDPOINT_KEY)
+	if _, err := login.get(OTDFCTL_REFRESH_TOKEN_KEY); err == nil {
+		status.Refreshable = true
+	} else if _, _, err := login.clientIdAndSecret(); err == nil {
+		status.Refreshable = true
+	}
+
+	token, err := login.get(OTDFCTL_OIDC_TOKEN_KEY)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached access token: %w", err)
+	}
+	claims := jwt.MapClaims{}
+	// the token is only inspected, the platform verifies it
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return nil, fmt.Errorf("failed to parse cached access token: %w", err)
+	}
+	if iss, ok := claims["iss"].(string); ok && iss != "" {
+		status.Issuer = iss
+	}
+	if sub, ok := claims["sub"].(string); ok {
+		status.Subject = sub
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		status.Expiry = time.Unix(int64(exp), 0)
+		status.Expired = !time.Now().Before(status.Expiry)
+	}
+	// OAuth2 uses a space separated 'scope' claim, while some IdPs issue a 'scp' list
+	if scope, ok := claims["scope"].(string); ok {
+		status.Scopes = strings.Fields(scope)
+	} else if scp, ok := claims["scp"].([]interface{}); ok {
+		for _, s := range scp {
+			status.Scopes = append(status.Scopes, fmt.Sprint(s))
+		}
+	}
+	return status, nil
+}
+
+// GetAccessToken returns the access token of the platform's cached login, obtaining a new one first when it has
+// expired. When acting as another subject, the token is exchanged for one of that subject.
+func (h Handler) GetAccessToken() (string, error) {
+	login, err := h.currentLogin()
+	if err != nil {
+		return "", err
+	}
+	token, _ := login.get(OTDFCTL_OIDC_TOKEN_KEY)
+	creds := &cachedTokenCredentials{ctx: h.ctx, login: login, actAs: h.actAs, token: token}
+	if token, err = creds.accessToken(); err != nil || h.actAs == "" {
+		return token, err
+	}
+	return creds.impersonationToken(token)
+}
+
+// Logout deletes the keyring entries of the platform's cached logins with every issuer, so connections are
+// unauthenticated again
+func (h Handler) Logout() error {
+	issuers, err := loginIssuers(h.platformEndpoint)
+	if err != nil {
+		return err
+	}
+	// logins cached before issuers were recorded are only known as the current one
+	if current, err := currentLogin(h.platformEndpoint); err == nil && !slices.Contains(issuers, current.issuer) {
+		issuers = append(issuers, current.issuer)
+	} else if err != nil && !errors.Is(err, credstore.ErrNotFound) {
+		return err
+	}
+	if len(issuers) == 0 {
+		return ErrNotLoggedIn
+	}
+
+	for _, issuer := range issuers {
+		if err := h.loginCache(issuer).clear(); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{OTDFCTL_ISSUER_KEY, OTDFCTL_ISSUERS_KEY} {
+		if err := credentials.Delete(platformService(h.platformEndpoint), key); err != nil && !errors.Is(err, credstore.ErrNotFound) {
+			return fmt.Errorf("failed to delete %s from keyring: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (h Handler) currentLogin() (loginCache, error) {
+	login, err := currentLogin(h.platformEndpoint)
+	if errors.Is(err, credstore.ErrNotFound) {
+		return loginCache{}, ErrNotLoggedIn
+	}
+	return login, err
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/credstore"
+	"golang.org/x/oauth2"
+)
+
+func TestLogout(t *testing.T) {
+	UseCredentialStore(credstore.NewMemory(nil))
+	t.Cleanup(func() { UseCredentialStore(credstore.NewKeyring()) })
+
+	h := Handler{platformEndpoint: testPlatform}
+	issuers := []string{"https://idp.example.com", "https://other-idp.example.com"}
+	for _, issuer := range issuers {
+		token := &oauth2.Token{AccessToken: "access-token", RefreshToken: "refresh-token"}
+		if err := cacheUserTokens(h.loginCache(issuer), issuer+"/token", testClientID, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a login to another platform is kept
+	other := Handler{platformEndpoint: "other.example.com"}
+	if err := cacheUserTokens(other.loginCache(issuers[0]), issuers[0]+"/token", testClientID, &oauth2.Token{AccessToken: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	for _, issuer := range issuers {
+		for _, key := range []string{OTDFCTL_OIDC_TOKEN_KEY, OTDFCTL_REFRESH_TOKEN_KEY, OTDFCTL_TOKEN_ENDPOINT_KEY, OTDFCTL_CLIENT_ID_CACHE_KEY} {
+			if v, err := h.loginCache(issuer).get(key); err == nil {
+				t.Errorf("expected %s of %s to be deleted, got %q", key, issuer, v)
+			}
+		}
+	}
+	if err := h.Logout(); !errors.Is(err, ErrNotLoggedIn) {
+		t.Errorf("expected logging out again to fail with ErrNotLoggedIn, got %v", err)
+	}
+	if token, err := other.GetOIDCTokenFromCache(); err != nil || token != "other" {
+		t.Errorf("expected the login to the other platform to be kept, got %q (%v)", token, err)
+	}
+}
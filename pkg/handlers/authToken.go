@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/opentdf/otdfctl/pkg/credstore"
+	"github.com/opentdf/platform/sdk"
+	"google.golang.org/grpc"
+)
+
+// cachedTokenCredentials authenticates every RPC with the access token cached in the keyring by 'auth login'.
+// Once CheckTokenExpiration reports the token expired, a new one is obtained with the cached refresh token of an
+// interactive login, or else with the cached client credentials. With actAs, the token is exchanged for one of
+// that subject (RFC 8693) before every RPC.
+type cachedTokenCredentials struct {
+	ctx        context.Context
+	login      loginCache
+	requireTLS bool
+	actAs      string
+
+	mu         sync.Mutex
+	token      string
+	actAsToken string
+}
+
+// cachedAuthOption returns the SDK option to authenticate with the platform's cached login, or nil when no one
+// is logged in to the platform so the connection is left unauthenticated. Acting as another subject requires
+// a login to exchange.
+func cachedAuthOption(ctx context.Context, platform string, requireTLS bool, actAs string) (sdk.Option, error) {
+	if login, err := currentLogin(platform); err == nil {
+		token, tokenErr := login.get(OTDFCTL_OIDC_TOKEN_KEY)
+		_, _, credsErr := login.clientIdAndSecret()
+		if (tokenErr == nil && token != "") || credsErr == nil {
+			creds := &cachedTokenCredentials{ctx: ctx, login: login, requireTLS: requireTLS, actAs: actAs, token: token}
+			return sdk.WithExtraDialOptions(grpc.WithPerRPCCredentials(creds)), nil
+		}
+	}
+
+	if actAs != "" {
+		return nil, fmt.Errorf("acting as %s requires a login to exchange: %w", actAs, ErrNotLoggedIn)
+	}
+	return nil, nil
+}
+
+func (c *cachedTokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := c.accessToken()
+	if err != nil {
+		return nil, err
+	}
+	if c.actAs != "" {
+		if token, err = c.impersonationToken(token); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// impersonationToken exchanges the login's access token for one of the actAs subject, reusing it until it
+// expires. The exchanged token is kept in memory only, so the cached login is left as it was.
+func (c *cachedTokenCredentials) impersonationToken(subjectToken string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.actAsToken != "" {
+		if valid, err := CheckTokenExpiration(c.actAsToken); err == nil && valid {
+			return c.actAsToken, nil
+		}
+	}
+
+	tokenURL, err := c.login.get(OTDFCTL_TOKEN_ENDPOINT_KEY)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached token endpoint: %w", err)
+	}
+	clientId, err := c.login.get(OTDFCTL_CLIENT_ID_CACHE_KEY)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached client ID: %w", err)
+	}
+	// a public client has no secret
+	clientSecret, _ := c.login.get(clientId)
+
+	token, err := exchangeToken(c.ctx, tokenURL, TokenExchangeOptions{
+		ClientID:         clientId,
+		ClientSecret:     clientSecret,
+		SubjectToken:     subjectToken,
+		RequestedSubject: c.actAs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to act as %s: %w", c.actAs, err)
+	}
+	c.actAsToken = token.AccessToken
+	return c.actAsToken, nil
+}
+
+func (c *cachedTokenCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
+
+func (c *cachedTokenCredentials) accessToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" {
+		if valid, err := CheckTokenExpiration(c.token); err == nil && valid {
+			return c.token, nil
+		}
+	}
+
+	if token, err := refreshCachedToken(c.ctx, c.login); err == nil {
+		c.token = token.AccessToken
+		return c.token, nil
+	} else if !errors.Is(err, credstore.ErrNotFound) {
+		return "", fmt.Errorf("failed to refresh access token: %w", err)
+	}
+
+	clientSecret, clientId, err := c.login.clientIdAndSecret()
+	if err != nil {
+		if errors.Is(err, credstore.ErrNotFound) {
+			return "", errors.New("cached access token expired and no client credentials are cached; run 'auth login' again")
+		}
+		return "", fmt.Errorf("failed to read cached client credentials: %w", err)
+	}
+
+	tokenURL, err := c.login.get(OTDFCTL_TOKEN_ENDPOINT_KEY)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cached token endpoint: %w", err)
+	}
+	token, err := getTokenWithClientCredentials(c.ctx, c.login, clientId, clientSecret, tokenURL, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	c.token = token.AccessToken
+	return c.token, nil
+}
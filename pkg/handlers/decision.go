@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// RuleEvaluation is the outcome of the rule of one attribute definition for the resource values of that attribute
+type RuleEvaluation struct {
+	AttributeFqn   string   `json:"attributeFqn"`
+	Rule           string   `json:"rule"`
+	ResourceValues []string `json:"resourceValues"`
+	// values of the attribute the entity is entitled to for the action
+	EntitledValues []string `json:"entitledValues"`
+	Permitted      bool     `json:"permitted"`
+	Explanation    string   `json:"explanation"`
+}
+
+// Decision is a simulated access decision for an entity, an action and the attribute values of a resource. Access
+// is permitted when the rules of all attribute definitions of the resource values are satisfied.
+type Decision struct {
+	Action       string           `json:"action"`
+	Permitted    bool             `json:"permitted"`
+	Rules        []RuleEvaluation `json:"rules"`
+	Entitlements []Entitlement    `json:"entitlements"`
+}
+
+// Decide simulates the access decision of the platform on the client. The entitlements of the entity come from
+// SimulateEntitlements, and only count when their subject mappings allow the action. Resource values are grouped by
+// attribute definition, whose rule and value order are taken from GetAttribute:
+//   - ALL_OF requires an entitlement to every resource value
+//   - ANY_OF requires an entitlement to at least one resource value
+//   - HIERARCHY requires an entitlement to the highest resource value or a value above it
+func (h Handler) Decide(entity map[string]interface{}, resourceValueFqns []string, action string) (*Decision, error) {
+	if len(resourceValueFqns) == 0 {
+		return nil, fmt.Errorf("at least one resource attribute value FQN is required")
+	}
+	sim, err := h.SimulateEntitlements(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &Decision{Action: action, Permitted: true, Rules: []RuleEvaluation{}, Entitlements: []Entitlement{}}
+	entitled := map[string]bool{}
+	for _, e := range sim.Entitlements {
+		for _, a := range e.Actions {
+			if strings.EqualFold(a, action) {
+				entitled[strings.ToLower(e.AttributeValueFqn)] = true
+				decision.Entitlements = append(decision.Entitlements, e)
+				break
+			}
+		}
+	}
+
+	// resource values by attribute, in the order the attributes are first given
+	var attrFqns []string
+	values := map[string][]Fqn{}
+	for _, ref := range resourceValueFqns {
+		f, err := ParseFqn(strings.TrimSpace(ref))
+		if err != nil {
+			return nil, err
+		}
+		if f.Value == "" {
+			return nil, fmt.Errorf("'%s' is not an attribute value FQN, i.e. https://<namespace>/attr/<name>/value/<value>", ref)
+		}
+		key := strings.ToLower(GetAttributeFqn(f.Namespace, f.Attribute))
+		if _, ok := values[key]; !ok {
+			attrFqns = append(attrFqns, key)
+		}
+		values[key] = append(values[key], f)
+	}
+
+	for _, attrFqn := range attrFqns {
+		id, err := h.ResolveAttributeId(attrFqn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find attribute %s: %w", attrFqn, err)
+		}
+		attr, err := h.GetAttribute(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attribute %s: %w", attrFqn, err)
+		}
+		result := evaluateAttributeRule(attr, values[attrFqn], entitled)
+		decision.Rules = append(decision.Rules, result)
+		decision.Permitted = decision.Permitted && result.Permitted
+	}
+	return decision, nil
+}
+
+func evaluateAttributeRule(attr *policy.Attribute, resourceValues []Fqn, entitled map[string]bool) RuleEvaluation {
+	ns := attr.GetNamespace().GetName()
+	result := RuleEvaluation{
+		AttributeFqn:   GetAttributeFqn(ns, attr.GetName()),
+		Rule:           GetAttributeRuleFromAttributeType(attr.GetRule()),
+		ResourceValues: []string{},
+		EntitledValues: []string{},
+	}
+
+	// the position of each value in the definition, where the first value ranks highest in a hierarchy
+	rank := map[string]int{}
+	for i, v := range attr.GetValues() {
+		fqn := GetAttributeValueFqn(ns, attr.GetName(), v.GetValue())
+		rank[strings.ToLower(v.GetValue())] = i
+		if entitled[strings.ToLower(fqn)] {
+			result.EntitledValues = append(result.EntitledValues, v.GetValue())
+		}
+	}
+
+	var missing, held []string
+	for _, f := range resourceValues {
+		result.ResourceValues = append(result.ResourceValues, f.Value)
+		if _, ok := rank[strings.ToLower(f.Value)]; !ok {
+			result.Explanation = fmt.Sprintf("value '%s' is not defined on the attribute", f.Value)
+			return result
+		}
+		if entitled[strings.ToLower(f.String())] {
+			held = append(held, f.Value)
+		} else {
+			missing = append(missing, f.Value)
+		}
+	}
+
+	switch result.Rule {
+	case AttributeRuleAllOf:
+		result.Permitted = len(missing) == 0
+		if result.Permitted {
+			result.Explanation = fmt.Sprintf("entitled to all of [%s]", strings.Join(result.ResourceValues, ", "))
+		} else {
+			result.Explanation = fmt.Sprintf("ALL_OF requires every resource value, not entitled to [%s]", strings.Join(missing, ", "))
+		}
+	case AttributeRuleAnyOf:
+		result.Permitted = len(held) > 0
+		if result.Permitted {
+			result.Explanation = fmt.Sprintf("entitled to [%s] of the resource values", strings.Join(held, ", "))
+		} else {
+			result.Explanation = fmt.Sprintf("ANY_OF requires one resource value, not entitled to any of [%s]", strings.Join(result.ResourceValues, ", "))
+		}
+	case AttributeRuleHierarchy:
+		required := resourceValues[0].Value
+		for _, f := range resourceValues[1:] {
+			if rank[strings.ToLower(f.Value)] < rank[strings.ToLower(required)] {
+				required = f.Value
+			}
+		}
+		if len(result.EntitledValues) == 0 {
+			result.Explanation = fmt.Sprintf("HIERARCHY requires '%s' or a higher value, not entitled to any value of the attribute", required)
+			break
+		}
+		// entitled values are in definition order, so the first is the highest
+		highest := result.EntitledValues[0]
+		result.Permitted = rank[strings.ToLower(highest)] <= rank[strings.ToLower(required)]
+		if result.Permitted {
+			result.Explanation = fmt.Sprintf("entitled to '%s', which is at or above the highest resource value '%s'", highest, required)
+		} else {
+			result.Explanation = fmt.Sprintf("HIERARCHY requires '%s' or a higher value, highest entitlement '%s' is below it", required, highest)
+		}
+	default:
+		result.Explanation = "attribute has no rule, so access cannot be decided"
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+func TestEvaluateAttributeRule(t *testing.T) {
+	attribute := func(rule policy.AttributeRuleTypeEnum, values ...string) *policy.Attribute {
+		attr := &policy.Attribute{Name: "level", Namespace: &policy.Namespace{Name: "example.com"}, Rule: rule}
+		for _, v := range values {
+			attr.Values = append(attr.Values, &policy.Value{Value: v})
+		}
+		return attr
+	}
+	allOf := attribute(policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_ALL_OF, "a", "b", "c")
+	anyOf := attribute(policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_ANY_OF, "a", "b", "c")
+	// the first value ranks highest
+	hierarchy := attribute(policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY, "top", "middle", "bottom")
+	noRule := attribute(policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_UNSPECIFIED, "a")
+
+	tests := []struct {
+		name           string
+		attr           *policy.Attribute
+		resourceValues []string
+		entitled       []string
+		permitted      bool
+		entitledValues []string
+		// text the explanation contains
+		explanation string
+	}{
+		{
+			name:           "ALL_OF entitled to every resource value",
+			attr:           allOf,
+			resourceValues: []string{"a", "b"},
+			entitled:       []string{"a", "b", "c"},
+			permitted:      true,
+			entitledValues: []string{"a", "b", "c"},
+			explanation:    "entitled to all of [a, b]",
+		},
+		{
+			name:           "ALL_OF missing a resource value",
+			attr:           allOf,
+			resourceValues: []string{"a", "b", "c"},
+			entitled:       []string{"B", "a"},
+			entitledValues: []string{"a", "b"},
+			explanation:    "not entitled to [c]",
+		},
+		{
+			name:           "ANY_OF entitled to one resource value",
+			attr:           anyOf,
+			resourceValues: []string{"a", "c"},
+			entitled:       []string{"c"},
+			permitted:      true,
+			entitledValues: []string{"c"},
+			explanation:    "entitled to [c]",
+		},
+		{
+			name:           "ANY_OF entitled to another value only",
+			attr:           anyOf,
+			resourceValues: []string{"a", "c"},
+			entitled:       []string{"b"},
+			entitledValues: []string{"b"},
+			explanation:    "not entitled to any of [a, c]",
+		},
+		{
+			name:           "HIERARCHY entitled to the required value",
+			attr:           hierarchy,
+			resourceValues: []string{"middle"},
+			entitled:       []string{"middle"},
+			permitted:      true,
+			entitledValues: []string{"middle"},
+			explanation:    "entitled to 'middle'",
+		},
+		{
+			name:           "HIERARCHY entitled to a higher value",
+			attr:           hierarchy,
+			resourceValues: []string{"bottom"},
+			entitled:       []string{"top"},
+			permitted:      true,
+			entitledValues: []string{"top"},
+			explanation:    "at or above the highest resource value 'bottom'",
+		},
+		{
+			name:           "HIERARCHY requires the highest resource value",
+			attr:           hierarchy,
+			resourceValues: []string{"bottom", "middle"},
+			entitled:       []string{"bottom"},
+			entitledValues: []string{"bottom"},
+			explanation:    "requires 'middle' or a higher value, highest entitlement 'bottom' is below it",
+		},
+		{
+			name:           "HIERARCHY uses the highest entitlement",
+			attr:           hierarchy,
+			resourceValues: []string{"middle"},
+			entitled:       []string{"bottom", "top"},
+			permitted:      true,
+			entitledValues: []string{"top", "bottom"},
+			explanation:    "entitled to 'top'",
+		},
+		{
+			name:           "HIERARCHY without entitlements",
+			attr:           hierarchy,
+			resourceValues: []string{"bottom"},
+			entitledValues: []string{},
+			explanation:    "not entitled to any value of the attribute",
+		},
+		{
+			name:           "ALL_OF without entitlements",
+			attr:           allOf,
+			resourceValues: []string{"a"},
+			entitledValues: []string{},
+			explanation:    "not entitled to [a]",
+		},
+		{
+			name:           "ANY_OF without entitlements",
+			attr:           anyOf,
+			resourceValues: []string{"a"},
+			entitledValues: []string{},
+			explanation:    "not entitled to any of [a]",
+		},
+		{
+			name:           "resource value not defined on the attribute",
+			attr:           anyOf,
+			resourceValues: []string{"a", "unknown"},
+			entitled:       []string{"a"},
+			entitledValues: []string{"a"},
+			explanation:    "value 'unknown' is not defined on the attribute",
+		},
+		{
+			name:           "attribute without a rule",
+			attr:           noRule,
+			resourceValues: []string{"a"},
+			entitled:       []string{"a"},
+			entitledValues: []string{"a"},
+			explanation:    "attribute has no rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, name := tt.attr.GetNamespace().GetName(), tt.attr.GetName()
+			var resourceValues []Fqn
+			for _, v := range tt.resourceValues {
+				resourceValues = append(resourceValues, Fqn{Namespace: ns, Attribute: name, Value: v})
+			}
+			entitled := map[string]bool{}
+			for _, v := range tt.entitled {
+				entitled[strings.ToLower(GetAttributeValueFqn(ns, name, v))] = true
+			}
+
+			result := evaluateAttributeRule(tt.attr, resourceValues, entitled)
+			if result.Permitted != tt.permitted {
+				t.Errorf("expected permitted %t, got %t (%s)", tt.permitted, result.Permitted, result.Explanation)
+			}
+			if !reflect.DeepEqual(result.EntitledValues, tt.entitledValues) {
+				t.Errorf("expected entitled values %v, got %v", tt.entitledValues, result.EntitledValues)
+			}
+			if !strings.Contains(result.Explanation, tt.explanation) {
+				t.Errorf("expected explanation containing %q, got %q", tt.explanation, result.Explanation)
+			}
+			if result.AttributeFqn != GetAttributeFqn(ns, name) {
+				t.Errorf("expected attribute %s, got %s", GetAttributeFqn(ns, name), result.AttributeFqn)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DependentKindNamespace           = "namespace"
+	DependentKindAttribute           = "attribute"
+	DependentKindAttributeValue      = "attribute value"
+	DependentKindSubjectConditionSet = "subject condition set"
+	DependentKindSubjectMapping      = "subject mapping"
+	DependentKindResourceMapping     = "resource mapping"
+	DependentKindKasRegistryEntry    = "kas registry entry"
+	DependentKindKasGrant            = "kas grant"
+)
+
+// Dependent is an object that references the target of a report, directly or through one of its children
+type Dependent struct {
+	Kind string `json:"kind"`
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// the FQN, name or id of the object the dependent references
+	DependsOn string `json:"dependsOn"`
+}
+
+// DependencyReport lists every object stranded when the target is deactivated or deleted: the attributes, values,
+// subject mappings, resource mappings and KAS grants below a namespace, attribute or value, the subject mappings of a
+// subject condition set, and the KAS grants of a KAS registry entry
+type DependencyReport struct {
+	Kind       string      `json:"kind"`
+	Id         string      `json:"id"`
+	Name       string      `json:"name"`
+	Dependents []Dependent `json:"dependents"`
+}
+
+// policyGraph holds the policy objects a report needs, as mappings and grants can only be found by listing them
+type policyGraph struct {
+	namespaces       []*policy.Namespace
+	attributes       []*policy.Attribute
+	values           map[string][]*policy.Value
+	conditionSets    []*policy.SubjectConditionSet
+	subjectMappings  []*policy.SubjectMapping
+	resourceMappings []*policy.ResourceMapping
+	kasUris          map[string]string
+}
+
+// loadPolicyGraph lists the objects the report of the kind needs, or all of them when the kind is not known. Values
+// are listed per attribute as only they carry their KAS grants, so they are only listed for the attributes that can
+// hold dependents of the target.
+func (h Handler) loadPolicyGraph(kind string, id string) (*policyGraph, error) {
+	g := &policyGraph{values: map[string][]*policy.Value{}, kasUris: map[string]string{}}
+	var err error
+	// attribute FQNs fall back to the namespace names
+	if g.namespaces, err = h.ListNamespaces(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	if g.attributes, err = h.ListAttributes(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY); err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %w", err)
+	}
+	listed := false
+	for _, attr := range g.attributes {
+		// the values of the attribute are enough to name the values of mappings
+		g.values[attr.GetId()] = attr.GetValues()
+		if g.listsValues(kind, id, attr) {
+			if err := h.listValues(g, attr); err != nil {
+				return nil, err
+			}
+			listed = true
+		}
+	}
+	// a value the attributes do not return, such as an inactive one, can only be found by listing all values
+	if kind == DependentKindAttributeValue && !listed {
+		for _, attr := range g.attributes {
+			if err := h.listValues(g, attr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kind == "" || kind == DependentKindSubjectConditionSet {
+		if g.conditionSets, err = h.ListSubjectConditionSets(); err != nil {
+			return nil, fmt.Errorf("failed to list subject condition sets: %w", err)
+		}
+	}
+	if kind != DependentKindKasRegistryEntry {
+		if g.subjectMappings, err = h.ListSubjectMappings(); err != nil {
+			return nil, fmt.Errorf("failed to list subject mappings: %w", err)
+		}
+	}
+	if kind != DependentKindKasRegistryEntry && kind != DependentKindSubjectConditionSet {
+		if g.resourceMappings, err = h.ListResourceMappings(); err != nil {
+			return nil, fmt.Errorf("failed to list resource mappings: %w", err)
+		}
+	}
+	if kind != DependentKindSubjectConditionSet {
+		kasList, err := h.ListKasRegistryEntries()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KAS registry entries: %w", err)
+		}
+		for _, kas := range kasList {
+			g.kasUris[kas.GetId()] = kas.GetUri()
+		}
+	}
+	return g, nil
+}
+
+func (h Handler) listValues(g *policyGraph, attr *policy.Attribute) error {
+	values, err := h.ListAttributeValues(attr.GetId(), common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return fmt.Errorf("failed to list values of attribute %s: %w", attr.GetId(), err)
+	}
+	g.values[attr.GetId()] = values
+	return nil
+}
+
+// listsValues reports whether the values of the attribute must be listed for the report of the object of the kind
+// with the id, which is when they or their grants can be among its dependents
+func (g *policyGraph) listsValues(kind string, id string, attr *policy.Attribute) bool {
+	switch kind {
+	case DependentKindNamespace:
+		name := g.namespaceNameById(id)
+		return attr.GetNamespace().GetId() == id || (name != "" && g.namespaceName(attr) == name)
+	case DependentKindAttribute:
+		return attr.GetId() == id
+	case DependentKindAttributeValue:
+		for _, v := range attr.GetValues() {
+			if v.GetId() == id {
+				return true
+			}
+		}
+		return false
+	case DependentKindSubjectConditionSet:
+		return false
+	}
+	return true
+}
+
+// GetDependents reports the dependents of the namespace, attribute, attribute value, subject condition set or KAS
+// registry entry with the given id
+func (h Handler) GetDependents(id string) (*DependencyReport, error) {
+	g, err := h.loadPolicyGraph("", id)
+	if err != nil {
+		return nil, err
+	}
+	if r := g.report(id); r != nil {
+		return r, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "no namespace, attribute, value, subject condition set or KAS registry entry with id %s", id)
+}
+
+func (h Handler) NamespaceDependents(id string) (*DependencyReport, error) {
+	return h.dependentsOfKind(DependentKindNamespace, id)
+}
+
+func (h Handler) AttributeDependents(id string) (*DependencyReport, error) {
+	return h.dependentsOfKind(DependentKindAttribute, id)
+}
+
+func (h Handler) AttributeValueDependents(id string) (*DependencyReport, error) {
+	return h.dependentsOfKind(DependentKindAttributeValue, id)
+}
+
+func (h Handler) SubjectConditionSetDependents(id string) (*DependencyReport, error) {
+	return h.dependentsOfKind(DependentKindSubjectConditionSet, id)
+}
+
+func (h Handler) KasRegistryEntryDependents(id string) (*DependencyReport, error) {
+	return h.dependentsOfKind(DependentKindKasRegistryEntry, id)
+}
+
+func (h Handler) dependentsOfKind(kind string, id string) (*DependencyReport, error) {
+	g, err := h.loadPolicyGraph(kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if r := g.report(id); r != nil && r.Kind == kind {
+		return r, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "no %s with id %s", kind, id)
+}
+
+// report finds the object with the id and collects its dependents, or returns nil if there is no such object
+func (g *policyGraph) report(id string) *DependencyReport {
+	for _, ns := range g.namespaces {
+		if ns.GetId() == id {
+			r := &DependencyReport{Kind: DependentKindNamespace, Id: id, Name: ns.GetName(), Dependents: []Dependent{}}
+			for _, attr := range g.attributes {
+				if attr.GetNamespace().GetId() == id || attr.GetNamespace().GetName() == ns.GetName() {
+					r.Dependents = append(r.Dependents, Dependent{
+						Kind:      DependentKindAttribute,
+						Id:        attr.GetId(),
+						Name:      g.attributeFqn(attr),
+						DependsOn: ns.GetName(),
+					})
+					r.Dependents = append(r.Dependents, g.attributeDependents(attr)...)
+				}
+			}
+			return r
+		}
+	}
+	for _, attr := range g.attributes {
+		if attr.GetId() == id {
+			return &DependencyReport{Kind: DependentKindAttribute, Id: id, Name: g.attributeFqn(attr), Dependents: g.attributeDependents(attr)}
+		}
+		for _, v := range g.values[attr.GetId()] {
+			if v.GetId() == id {
+				return &DependencyReport{Kind: DependentKindAttributeValue, Id: id, Name: g.valueFqn(attr, v), Dependents: g.valueDependents(attr, v)}
+			}
+		}
+	}
+	for _, scs := range g.conditionSets {
+		if scs.GetId() == id {
+			r := &DependencyReport{Kind: DependentKindSubjectConditionSet, Id: id, Name: id, Dependents: []Dependent{}}
+			for _, sm := range g.subjectMappings {
+				if sm.GetSubjectConditionSet().GetId() == id {
+					r.Dependents = append(r.Dependents, Dependent{
+						Kind:      DependentKindSubjectMapping,
+						Id:        sm.GetId(),
+						Name:      g.mappedValueFqn(sm.GetAttributeValue()) + " <- " + id,
+						DependsOn: id,
+					})
+				}
+			}
+			return r
+		}
+	}
+	if uri, ok := g.kasUris[id]; ok {
+		r := &DependencyReport{Kind: DependentKindKasRegistryEntry, Id: id, Name: uri, Dependents: []Dependent{}}
+		for _, attr := range g.attributes {
+			if hasGrant(attr.GetGrants(), id) {
+				r.Dependents = append(r.Dependents, g.grant(attr.GetId(), g.attributeFqn(attr), id))
+			}
+			for _, v := range g.values[attr.GetId()] {
+				if hasGrant(v.GetGrants(), id) {
+					r.Dependents = append(r.Dependents, g.grant(v.GetId(), g.valueFqn(attr, v), id))
+				}
+			}
+		}
+		return r
+	}
+	return nil
+}
+
+func (g *policyGraph) attributeDependents(attr *policy.Attribute) []Dependent {
+	fqn := g.attributeFqn(attr)
+	dependents := []Dependent{}
+	for _, kas := range attr.GetGrants() {
+		dependents = append(dependents, g.grant(attr.GetId(), fqn, kas.GetId()))
+	}
+	for _, v := range g.values[attr.GetId()] {
+		dependents = append(dependents, Dependent{
+			Kind:      DependentKindAttributeValue,
+			Id:        v.GetId(),
+			Name:      g.valueFqn(attr, v),
+			DependsOn: fqn,
+		})
+		dependents = append(dependents, g.valueDependents(attr, v)...)
+	}
+	return dependents
+}
+
+func (g *policyGraph) valueDependents(attr *policy.Attribute, v *policy.Value) []Dependent {
+	fqn := g.valueFqn(attr, v)
+	dependents := []Dependent{}
+	for _, sm := range g.subjectMappings {
+		if sm.GetAttributeValue().GetId() == v.GetId() {
+			dependents = append(dependents, Dependent{
+				Kind:      DependentKindSubjectMapping,
+				Id:        sm.GetId(),
+				Name:      fqn + " <- " + sm.GetSubjectConditionSet().GetId(),
+				DependsOn: fqn,
+			})
+		}
+	}
+	for _, rm := range g.resourceMappings {
+		if rm.GetAttributeValue().GetId() == v.GetId() {
+			dependents = append(dependents, Dependent{
+				Kind:      DependentKindResourceMapping,
+				Id:        rm.GetId(),
+				Name:      fmt.Sprintf("%s %v", fqn, rm.GetTerms()),
+				DependsOn: fqn,
+			})
+		}
+	}
+	for _, kas := range v.GetGrants() {
+		dependents = append(dependents, g.grant(v.GetId(), fqn, kas.GetId()))
+	}
+	return dependents
+}
+
+// grant describes a KAS grant as '<attribute or value FQN> -> <KAS URI>', with the id of the granted object
+func (g *policyGraph) grant(id string, fqn string, kasId string) Dependent {
+	uri, ok := g.kasUris[kasId]
+	if !ok {
+		uri = kasId
+	}
+	return Dependent{Kind: DependentKindKasGrant, Id: id, Name: fqn + " -> " + uri, DependsOn: fqn}
+}
+
+func (g *policyGraph) attributeFqn(attr *policy.Attribute) string {
+	return GetAttributeFqn(g.namespaceName(attr), attr.GetName())
+}
+
+func (g *policyGraph) valueFqn(attr *policy.Attribute, v *policy.Value) string {
+	return GetAttributeValueFqn(g.namespaceName(attr), attr.GetName(), v.GetValue())
+}
+
+func (g *policyGraph) namespaceName(attr *policy.Attribute) string {
+	if name := attr.GetNamespace().GetName(); name != "" {
+		return name
+	}
+	return g.namespaceNameById(attr.GetNamespace().GetId())
+}
+
+func (g *policyGraph) namespaceNameById(id string) string {
+	for _, ns := range g.namespaces {
+		if ns.GetId() == id {
+			return ns.GetName()
+		}
+	}
+	return ""
+}
+
+// mappedValueFqn returns the FQN of a value referenced by a mapping, which only carries its id and value
+func (g *policyGraph) mappedValueFqn(v *policy.Value) string {
+	for _, attr := range g.attributes {
+		for _, candidate := range g.values[attr.GetId()] {
+			if candidate.GetId() == v.GetId() {
+				return g.valueFqn(attr, candidate)
+			}
+		}
+	}
+	return v.GetValue()
+}
+
+func hasGrant(grants []*kasregistry.KeyAccessServer, kasId string) bool {
+	for _, kas := range grants {
+		if kas.GetId() == kasId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+func testPolicyGraph() *policyGraph {
+	kas := &kasregistry.KeyAccessServer{Id: "kas-1", Uri: "https://kas.example.com"}
+	high := &policy.Value{Id: "v-high", Value: "high", Grants: []*kasregistry.KeyAccessServer{kas}}
+	low := &policy.Value{Id: "v-low", Value: "low"}
+	eng := &policy.Value{Id: "v-eng", Value: "eng"}
+	scs := &policy.SubjectConditionSet{Id: "scs-1"}
+	return &policyGraph{
+		namespaces: []*policy.Namespace{{Id: "ns-1", Name: "example.com"}, {Id: "ns-2", Name: "other.com"}},
+		attributes: []*policy.Attribute{
+			// an attribute that only carries the id of its namespace
+			{Id: "attr-1", Namespace: &policy.Namespace{Id: "ns-1"}, Name: "level", Grants: []*kasregistry.KeyAccessServer{kas}, Values: []*policy.Value{{Id: "v-high", Value: "high"}}},
+			{Id: "attr-2", Namespace: &policy.Namespace{Id: "ns-2", Name: "other.com"}, Name: "team"},
+		},
+		values: map[string][]*policy.Value{
+			"attr-1": {high, low},
+			"attr-2": {eng},
+		},
+		conditionSets: []*policy.SubjectConditionSet{scs},
+		subjectMappings: []*policy.SubjectMapping{
+			// mappings only carry the id and value of their value
+			{Id: "sm-1", AttributeValue: &policy.Value{Id: "v-high", Value: "high"}, SubjectConditionSet: scs},
+			{Id: "sm-2", AttributeValue: &policy.Value{Id: "v-eng", Value: "eng"}, SubjectConditionSet: scs},
+		},
+		resourceMappings: []*policy.ResourceMapping{
+			{Id: "rm-1", AttributeValue: &policy.Value{Id: "v-low", Value: "low"}, Terms: []string{"public"}},
+		},
+		kasUris: map[string]string{"kas-1": "https://kas.example.com"},
+	}
+}
+
+func TestPolicyGraphReport(t *testing.T) {
+	const (
+		level = "https://example.com/attr/level"
+		high  = "https://example.com/attr/level/value/high"
+		low   = "https://example.com/attr/level/value/low"
+		eng   = "https://other.com/attr/team/value/eng"
+	)
+
+	tests := []struct {
+		name string
+		id   string
+		want *DependencyReport
+	}{
+		{
+			name: "namespace",
+			id:   "ns-1",
+			want: &DependencyReport{Kind: DependentKindNamespace, Id: "ns-1", Name: "example.com", Dependents: []Dependent{
+				{Kind: DependentKindAttribute, Id: "attr-1", Name: level, DependsOn: "example.com"},
+				{Kind: DependentKindKasGrant, Id: "attr-1", Name: level + " -> https://kas.example.com", DependsOn: level},
+				{Kind: DependentKindAttributeValue, Id: "v-high", Name: high, DependsOn: level},
+				{Kind: DependentKindSubjectMapping, Id: "sm-1", Name: high + " <- scs-1", DependsOn: high},
+				{Kind: DependentKindKasGrant, Id: "v-high", Name: high + " -> https://kas.example.com", DependsOn: high},
+				{Kind: DependentKindAttributeValue, Id: "v-low", Name: low, DependsOn: level},
+				{Kind: DependentKindResourceMapping, Id: "rm-1", Name: low + " [public]", DependsOn: low},
+			}},
+		},
+		{
+			name: "attribute",
+			id:   "attr-2",
+			want: &DependencyReport{Kind: DependentKindAttribute, Id: "attr-2", Name: "https://other.com/attr/team", Dependents: []Dependent{
+				{Kind: DependentKindAttributeValue, Id: "v-eng", Name: eng, DependsOn: "https://other.com/attr/team"},
+				{Kind: DependentKindSubjectMapping, Id: "sm-2", Name: eng + " <- scs-1", DependsOn: eng},
+			}},
+		},
+		{
+			name: "value",
+			id:   "v-low",
+			want: &DependencyReport{Kind: DependentKindAttributeValue, Id: "v-low", Name: low, Dependents: []Dependent{
+				{Kind: DependentKindResourceMapping, Id: "rm-1", Name: low + " [public]", DependsOn: low},
+			}},
+		},
+		{
+			name: "subject condition set",
+			id:   "scs-1",
+			want: &DependencyReport{Kind: DependentKindSubjectConditionSet, Id: "scs-1", Name: "scs-1", Dependents: []Dependent{
+				{Kind: DependentKindSubjectMapping, Id: "sm-1", Name: high + " <- scs-1", DependsOn: "scs-1"},
+				{Kind: DependentKindSubjectMapping, Id: "sm-2", Name: eng + " <- scs-1", DependsOn: "scs-1"},
+			}},
+		},
+		{
+			name: "KAS registry entry",
+			id:   "kas-1",
+			want: &DependencyReport{Kind: DependentKindKasRegistryEntry, Id: "kas-1", Name: "https://kas.example.com", Dependents: []Dependent{
+				{Kind: DependentKindKasGrant, Id: "attr-1", Name: level + " -> https://kas.example.com", DependsOn: level},
+				{Kind: DependentKindKasGrant, Id: "v-high", Name: high + " -> https://kas.example.com", DependsOn: high},
+			}},
+		},
+		{
+			name: "unknown id",
+			id:   "missing",
+		},
+	}
+
+	g := testPolicyGraph()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.report(tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected report\n%+v\ngot\n%+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPolicyGraphListsValues(t *testing.T) {
+	g := testPolicyGraph()
+	tests := []struct {
+		kind string
+		id   string
+		// the attributes whose values are listed
+		want []string
+	}{
+		{kind: "", id: "ns-1", want: []string{"attr-1", "attr-2"}},
+		{kind: DependentKindNamespace, id: "ns-1", want: []string{"attr-1"}},
+		{kind: DependentKindNamespace, id: "missing"},
+		{kind: DependentKindAttribute, id: "attr-2", want: []string{"attr-2"}},
+		{kind: DependentKindAttributeValue, id: "v-high", want: []string{"attr-1"}},
+		{kind: DependentKindSubjectConditionSet, id: "scs-1"},
+		{kind: DependentKindKasRegistryEntry, id: "kas-1", want: []string{"attr-1", "attr-2"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, attr := range g.attributes {
+			if g.listsValues(tt.kind, tt.id, attr) {
+				got = append(got, attr.GetId())
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expected the values of %v to be listed for %s %s, got %v", tt.want, tt.kind, tt.id, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DryRunError is returned by a mutating handler in dry-run mode in place of calling the RPC. It carries the
+// exact request that would have been sent and the current server state of the object it would have changed.
+type DryRunError struct {
+	Rpc     string        `json:"rpc"`
+	Request proto.Message `json:"request"`
+	Current proto.Message `json:"current"`
+	// objects stranded by a deactivation or deletion, or why they could not be found
+	Dependents      []Dependent `json:"dependents,omitempty"`
+	DependentsError string      `json:"dependentsError,omitempty"`
+}
+
+// MarshalJSON prints the request and current state with protojson, as the API and the json output show them
+func (e *DryRunError) MarshalJSON() ([]byte, error) {
+	request, err := marshalProtoJSON(e.Request)
+	if err != nil {
+		return nil, err
+	}
+	current, err := marshalProtoJSON(e.Current)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Rpc             string          `json:"rpc"`
+		Request         json.RawMessage `json:"request"`
+		Current         json.RawMessage `json:"current"`
+		Dependents      []Dependent     `json:"dependents,omitempty"`
+		DependentsError string          `json:"dependentsError,omitempty"`
+	}{e.Rpc, request, current, e.Dependents, e.DependentsError})
+}
+
+func marshalProtoJSON(m proto.Message) (json.RawMessage, error) {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return json.RawMessage("null"), nil
+	}
+	return protojson.Marshal(m)
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("dry run: %s was not sent", e.Rpc)
+}
+
+// dryRun builds the DryRunError for a planned mutation, or returns the error from fetching the current state
+func dryRun(rpc string, req proto.Message, current proto.Message, err error) error {
+	if err != nil {
+		return err
+	}
+	return &DryRunError{
+		Rpc:     rpc,
+		Request: req,
+		Current: current,
+	}
+}
+
+// withDependents adds the dependents of the target of a planned deactivation or deletion to its DryRunError. The
+// plan is still printed when they cannot be found.
+func withDependents(err error, dependents func(string) (*DependencyReport, error), id string) error {
+	var plan *DryRunError
+	if !errors.As(err, &plan) {
+		return err
+	}
+	report, depErr := dependents(id)
+	if depErr != nil {
+		plan.DependentsError = depErr.Error()
+		return plan
+	}
+	plan.Dependents = report.Dependents
+	return plan
+}
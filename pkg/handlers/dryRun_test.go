@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithDependents(t *testing.T) {
+	dependents := []Dependent{{Kind: DependentKindSubjectMapping, Id: "sm", Name: "sm", DependsOn: "https://example.com/attr/a/value/b"}}
+	found := func(id string) (*DependencyReport, error) {
+		return &DependencyReport{Kind: DependentKindAttributeValue, Id: id, Dependents: dependents}, nil
+	}
+	failed := func(string) (*DependencyReport, error) {
+		return nil, errors.New("list failed")
+	}
+
+	notFound := errors.New("not found")
+	if err := withDependents(notFound, found, "id"); err != notFound {
+		t.Errorf("expected other errors to be returned as they are, got %v", err)
+	}
+
+	err := withDependents(dryRun("DeactivateAttributeValue", nil, nil, nil), found, "id")
+	var plan *DryRunError
+	if !errors.As(err, &plan) {
+		t.Fatalf("expected a dry run plan, got %v", err)
+	}
+	if !reflect.DeepEqual(plan.Dependents, dependents) {
+		t.Errorf("expected dependents %v, got %v", dependents, plan.Dependents)
+	}
+	b, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var printed struct {
+		Dependents []Dependent `json:"dependents"`
+	}
+	if err := json.Unmarshal(b, &printed); err != nil || !reflect.DeepEqual(printed.Dependents, dependents) {
+		t.Errorf("expected the dependents in the printed plan, got %s", b)
+	}
+
+	// the plan is still printed when the dependents cannot be found
+	err = withDependents(dryRun("DeactivateAttributeValue", nil, nil, nil), failed, "id")
+	if !errors.As(err, &plan) || plan.DependentsError != "list failed" || plan.Dependents != nil {
+		t.Errorf("expected a plan with the dependents error, got %+v", err)
+	}
+}
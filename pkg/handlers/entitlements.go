@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// Entitlement is an attribute value an entity is entitled to, with the actions allowed by the subject mappings whose
+// condition sets matched its claims
+type Entitlement struct {
+	AttributeValueFqn string   `json:"attributeValueFqn"`
+	AttributeValueId  string   `json:"attributeValueId"`
+	Actions           []string `json:"actions"`
+	SubjectMappingIds []string `json:"subjectMappingIds"`
+}
+
+// SubjectMappingEvaluation is the outcome of one subject mapping against the claims of an entity
+type SubjectMappingEvaluation struct {
+	SubjectMappingId  string                        `json:"subjectMappingId"`
+	AttributeValueFqn string                        `json:"attributeValueFqn"`
+	Actions           []string                      `json:"actions"`
+	Evaluation        SubjectConditionSetEvaluation `json:"evaluation"`
+}
+
+type EntitlementSimulation struct {
+	Entitlements []Entitlement              `json:"entitlements"`
+	Mappings     []SubjectMappingEvaluation `json:"subjectMappings"`
+}
+
+// SimulateEntitlements evaluates the subject condition set of every subject mapping against the entity claims on
+// the client, the same way the platform decides entitlements, and returns the attribute values of the mappings that
+// matched. Values of inactive attributes are still listed, as the mappings referencing them remain.
+func (h Handler) SimulateEntitlements(entity map[string]interface{}) (*EntitlementSimulation, error) {
+	mappings, err := h.ListSubjectMappings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subject mappings: %w", err)
+	}
+	fqns, err := h.attributeValueFqns()
+	if err != nil {
+		return nil, err
+	}
+	return simulateEntitlements(mappings, fqns, entity), nil
+}
+
+// simulateEntitlements evaluates the mappings against the entity claims and merges the actions of the mappings that
+// matched per attribute value. Values are named by the FQN of their id, or by the value itself when it is unknown.
+func simulateEntitlements(mappings []*policy.SubjectMapping, fqns map[string]string, entity map[string]interface{}) *EntitlementSimulation {
+	sim := &EntitlementSimulation{Entitlements: []Entitlement{}, Mappings: []SubjectMappingEvaluation{}}
+	byValue := map[string]*Entitlement{}
+	for _, sm := range mappings {
+		valueId := sm.GetAttributeValue().GetId()
+		fqn, ok := fqns[valueId]
+		if !ok {
+			fqn = sm.GetAttributeValue().GetValue()
+		}
+		eval := SubjectMappingEvaluation{
+			SubjectMappingId:  sm.GetId(),
+			AttributeValueFqn: fqn,
+			Actions:           GetActionNames(sm.GetActions()),
+			Evaluation:        EvaluateSubjectConditionSet(sm.GetSubjectConditionSet(), entity),
+		}
+		sim.Mappings = append(sim.Mappings, eval)
+		if !eval.Evaluation.Matched {
+			continue
+		}
+
+		e, ok := byValue[valueId]
+		if !ok {
+			e = &Entitlement{AttributeValueFqn: fqn, AttributeValueId: valueId, Actions: []string{}}
+			byValue[valueId] = e
+		}
+		for _, a := range eval.Actions {
+			if !slices.Contains(e.Actions, a) {
+				e.Actions = append(e.Actions, a)
+			}
+		}
+		e.SubjectMappingIds = append(e.SubjectMappingIds, sm.GetId())
+	}
+
+	for _, e := range byValue {
+		sort.Strings(e.Actions)
+		sim.Entitlements = append(sim.Entitlements, *e)
+	}
+	sort.Slice(sim.Entitlements, func(i, j int) bool {
+		return sim.Entitlements[i].AttributeValueFqn < sim.Entitlements[j].AttributeValueFqn
+	})
+	return sim
+}
+
+// GetActionNames returns the readable names of standard actions and the names of custom actions
+func GetActionNames(actions []*policy.Action) []string {
+	names := []string{}
+	for _, a := range actions {
+		if custom := a.GetCustom(); custom != "" {
+			names = append(names, custom)
+		} else if standard := GetSubjectMappingActionChoiceFromEnum(a.GetStandard()); standard != "" {
+			names = append(names, standard)
+		}
+	}
+	return names
+}
+
+// Maps the ids of all attribute values to their FQNs
+func (h Handler) attributeValueFqns() (map[string]string, error) {
+	attrs, err := h.ListAttributes(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %w", err)
+	}
+	fqns := map[string]string{}
+	for _, attr := range attrs {
+		for _, v := range attr.GetValues() {
+			fqns[v.GetId()] = GetAttributeValueFqn(attr.GetNamespace().GetName(), attr.GetName(), v.GetValue())
+		}
+	}
+	return fqns, nil
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+func TestSimulateEntitlements(t *testing.T) {
+	condition := func(values ...string) *policy.SubjectConditionSet {
+		return &policy.SubjectConditionSet{SubjectSets: []*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{{
+			BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND,
+			Conditions: []*policy.Condition{{
+				SubjectExternalField:  ".department",
+				Operator:              policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN,
+				SubjectExternalValues: values,
+			}},
+		}}}}}
+	}
+	matches, noMatch := condition("eng"), condition("sales")
+	decrypt := &policy.Action{Value: &policy.Action_Standard{Standard: policy.Action_STANDARD_ACTION_DECRYPT}}
+	transmit := &policy.Action{Value: &policy.Action_Standard{Standard: policy.Action_STANDARD_ACTION_TRANSMIT}}
+	audit := &policy.Action{Value: &policy.Action_Custom{Custom: "audit"}}
+	mapping := func(id, valueId string, scs *policy.SubjectConditionSet, actions ...*policy.Action) *policy.SubjectMapping {
+		return &policy.SubjectMapping{
+			Id:                  id,
+			AttributeValue:      &policy.Value{Id: valueId, Value: valueId + "-value"},
+			SubjectConditionSet: scs,
+			Actions:             actions,
+		}
+	}
+	fqns := map[string]string{
+		"v-high": "https://example.com/attr/level/value/high",
+		"v-low":  "https://example.com/attr/level/value/low",
+		"v-eng":  "https://example.com/attr/team/value/eng",
+	}
+
+	tests := []struct {
+		name     string
+		mappings []*policy.SubjectMapping
+		want     []Entitlement
+	}{
+		{
+			name:     "no mappings",
+			mappings: []*policy.SubjectMapping{},
+			want:     []Entitlement{},
+		},
+		{
+			name: "actions merged per value",
+			mappings: []*policy.SubjectMapping{
+				mapping("sm-1", "v-high", matches, transmit, decrypt),
+				mapping("sm-2", "v-high", matches, decrypt, audit),
+			},
+			want: []Entitlement{
+				{AttributeValueFqn: fqns["v-high"], AttributeValueId: "v-high", Actions: []string{"DECRYPT", "TRANSMIT", "audit"}, SubjectMappingIds: []string{"sm-1", "sm-2"}},
+			},
+		},
+		{
+			name: "only matching mappings entitle",
+			mappings: []*policy.SubjectMapping{
+				mapping("sm-1", "v-low", matches, decrypt),
+				mapping("sm-2", "v-low", noMatch, transmit),
+				mapping("sm-3", "v-high", noMatch, decrypt),
+			},
+			want: []Entitlement{
+				{AttributeValueFqn: fqns["v-low"], AttributeValueId: "v-low", Actions: []string{"DECRYPT"}, SubjectMappingIds: []string{"sm-1"}},
+			},
+		},
+		{
+			name: "values sorted by FQN",
+			mappings: []*policy.SubjectMapping{
+				mapping("sm-1", "v-eng", matches, decrypt),
+				mapping("sm-2", "v-low", matches, decrypt),
+				mapping("sm-3", "v-high", matches),
+			},
+			want: []Entitlement{
+				{AttributeValueFqn: fqns["v-high"], AttributeValueId: "v-high", Actions: []string{}, SubjectMappingIds: []string{"sm-3"}},
+				{AttributeValueFqn: fqns["v-low"], AttributeValueId: "v-low", Actions: []string{"DECRYPT"}, SubjectMappingIds: []string{"sm-2"}},
+				{AttributeValueFqn: fqns["v-eng"], AttributeValueId: "v-eng", Actions: []string{"DECRYPT"}, SubjectMappingIds: []string{"sm-1"}},
+			},
+		},
+		{
+			name: "unknown value id falls back to the value",
+			mappings: []*policy.SubjectMapping{
+				mapping("sm-1", "v-unknown", matches, decrypt),
+			},
+			want: []Entitlement{
+				{AttributeValueFqn: "v-unknown-value", AttributeValueId: "v-unknown", Actions: []string{"DECRYPT"}, SubjectMappingIds: []string{"sm-1"}},
+			},
+		},
+	}
+
+	entity := map[string]interface{}{"department": "eng"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := simulateEntitlements(tt.mappings, fqns, entity)
+			if !reflect.DeepEqual(sim.Entitlements, tt.want) {
+				t.Errorf("expected entitlements\n%+v\ngot\n%+v", tt.want, sim.Entitlements)
+			}
+			if len(sim.Mappings) != len(tt.mappings) {
+				t.Fatalf("expected an evaluation of every mapping, got %+v", sim.Mappings)
+			}
+			for i, eval := range sim.Mappings {
+				sm := tt.mappings[i]
+				if eval.SubjectMappingId != sm.GetId() || eval.Evaluation.Matched != (sm.GetSubjectConditionSet() == matches) {
+					t.Errorf("expected the evaluation of mapping %s in order, got %+v", sm.GetId(), eval)
+				}
+			}
+		})
+	}
+}
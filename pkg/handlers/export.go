@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// exportedPolicy is the platform policy an export is built from
+type exportedPolicy struct {
+	kasRegistry []*kasregistry.KeyAccessServer
+	namespaces  []*policy.Namespace
+	attributes  []*policy.Attribute
+	// listed values of each attribute by attribute id, including inactive values and KAS grants
+	values               map[string][]*policy.Value
+	subjectConditionSets []*policy.SubjectConditionSet
+	subjectMappings      []*policy.SubjectMapping
+	resourceMappings     []*policy.ResourceMapping
+}
+
+// ExportManifest walks all policy on the platform and returns it as a self-consistent manifest in which
+// every cross-reference is an FQN, name, or URI instead of a server id.
+func (h Handler) ExportManifest() (*manifest.Manifest, error) {
+	p := exportedPolicy{values: map[string][]*policy.Value{}}
+	var err error
+
+	if p.kasRegistry, err = h.ListKasRegistryEntries(); err != nil {
+		return nil, fmt.Errorf("failed to list KAS registry entries: %w", err)
+	}
+	if p.namespaces, err = h.ListNamespaces(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	if p.attributes, err = h.ListAttributes(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY); err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %w", err)
+	}
+	for _, attr := range p.attributes {
+		values, err := h.ListAttributeValues(attr.GetId(), common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list values of attribute %s: %w", GetAttributeFqn(attr.GetNamespace().GetName(), attr.GetName()), err)
+		}
+		p.values[attr.GetId()] = values
+	}
+	if p.subjectConditionSets, err = h.ListSubjectConditionSets(); err != nil {
+		return nil, fmt.Errorf("failed to list subject condition sets: %w", err)
+	}
+	if p.subjectMappings, err = h.ListSubjectMappings(); err != nil {
+		return nil, fmt.Errorf("failed to list subject mappings: %w", err)
+	}
+	if p.resourceMappings, err = h.ListResourceMappings(); err != nil {
+		return nil, fmt.Errorf("failed to list resource mappings: %w", err)
+	}
+	return exportManifest(p), nil
+}
+
+func exportManifest(p exportedPolicy) *manifest.Manifest {
+	m := &manifest.Manifest{}
+
+	kasUris := map[string]string{}
+	for _, kas := range p.kasRegistry {
+		kasUris[kas.GetId()] = kas.GetUri()
+		m.KasRegistry = append(m.KasRegistry, manifest.KasRegistryEntry{
+			Uri: kas.GetUri(),
+			PublicKey: manifest.PublicKey{
+				Local:  kas.GetPublicKey().GetLocal(),
+				Remote: kas.GetPublicKey().GetRemote(),
+			},
+			Labels: exportLabels(kas.GetMetadata()),
+		})
+	}
+
+	for _, ns := range p.namespaces {
+		m.Namespaces = append(m.Namespaces, manifest.Namespace{
+			Name:   ns.GetName(),
+			Active: exportActive(ns.GetActive().GetValue()),
+			Labels: exportLabels(ns.GetMetadata()),
+		})
+	}
+
+	valueFqns := map[string]string{}
+	for _, attr := range p.attributes {
+		ns := attr.GetNamespace().GetName()
+		attrFqn := GetAttributeFqn(ns, attr.GetName())
+		a := manifest.Attribute{
+			Namespace: ns,
+			Name:      attr.GetName(),
+			Rule:      GetAttributeRuleFromAttributeType(attr.GetRule()),
+			Active:    exportActive(attr.GetActive().GetValue()),
+			Labels:    exportLabels(attr.GetMetadata()),
+		}
+		for _, v := range orderedValues(attr, p.values[attr.GetId()]) {
+			fqn := GetAttributeValueFqn(ns, attr.GetName(), v.GetValue())
+			valueFqns[v.GetId()] = fqn
+			a.Values = append(a.Values, manifest.Value{
+				Value:  v.GetValue(),
+				Active: exportActive(v.GetActive().GetValue()),
+				Labels: exportLabels(v.GetMetadata()),
+			})
+			for _, g := range v.GetGrants() {
+				m.KasGrants = append(m.KasGrants, manifest.KasGrant{Kas: exportKasUri(kasUris, g), Value: fqn})
+			}
+		}
+		for _, g := range attr.GetGrants() {
+			m.KasGrants = append(m.KasGrants, manifest.KasGrant{Kas: exportKasUri(kasUris, g), Attribute: attrFqn})
+		}
+		m.Attributes = append(m.Attributes, a)
+	}
+
+	scsNames := map[string]string{}
+	nameCounts := map[string]int{}
+	for _, scs := range p.subjectConditionSets {
+		sets := SubjectSetsToManifest(scs.GetSubjectSets())
+		// sets with identical content share a derived name, so later ones are numbered to keep names unique
+		name := SubjectConditionSetManifestName(sets)
+		nameCounts[name]++
+		if n := nameCounts[name]; n > 1 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		scsNames[scs.GetId()] = name
+		m.SubjectConditionSets = append(m.SubjectConditionSets, manifest.SubjectConditionSet{
+			Name:        name,
+			SubjectSets: sets,
+			Labels:      exportLabels(scs.GetMetadata()),
+		})
+	}
+
+	for _, sm := range p.subjectMappings {
+		m.SubjectMappings = append(m.SubjectMappings, manifest.SubjectMapping{
+			AttributeValue:      exportValueFqn(valueFqns, sm.GetAttributeValue()),
+			SubjectConditionSet: scsNames[sm.GetSubjectConditionSet().GetId()],
+			Actions:             ActionsToManifest(sm.GetActions()),
+			Labels:              exportLabels(sm.GetMetadata()),
+		})
+	}
+
+	for _, rm := range p.resourceMappings {
+		m.ResourceMappings = append(m.ResourceMappings, manifest.ResourceMapping{
+			AttributeValue: exportValueFqn(valueFqns, rm.GetAttributeValue()),
+			Terms:          rm.GetTerms(),
+			Labels:         exportLabels(rm.GetMetadata()),
+		})
+	}
+
+	sortManifest(m)
+	return m
+}
+
+// The values of an attribute are in the order that ranks a hierarchy, which listing its values does not
+// guarantee. Listed values still carry the KAS grants, and inactive values are only listed, so they follow.
+func orderedValues(attr *policy.Attribute, listed []*policy.Value) []*policy.Value {
+	byId := map[string]*policy.Value{}
+	for _, v := range listed {
+		byId[v.GetId()] = v
+	}
+	ordered := []*policy.Value{}
+	seen := map[string]bool{}
+	for _, v := range attr.GetValues() {
+		if l, ok := byId[v.GetId()]; ok {
+			v = l
+		}
+		ordered = append(ordered, v)
+		seen[v.GetId()] = true
+	}
+	for _, v := range listed {
+		if !seen[v.GetId()] {
+			ordered = append(ordered, v)
+		}
+	}
+	return ordered
+}
+
+// SubjectConditionSetManifestName derives a stable local name from the content of a subject condition set,
+// so the same set exported from different platforms is given the same name
+func SubjectConditionSetManifestName(sets []manifest.SubjectSet) string {
+	sum := sha256.Sum256([]byte(manifest.SubjectSetsKey(sets)))
+	return "scs-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func exportLabels(m *common.Metadata) map[string]string {
+	if len(m.GetLabels()) == 0 {
+		return nil
+	}
+	return m.GetLabels()
+}
+
+// Active is the default, so only inactive objects carry explicit state in a manifest
+func exportActive(active bool) *bool {
+	if active {
+		return nil
+	}
+	return &active
+}
+
+func exportKasUri(kasUris map[string]string, kas *kasregistry.KeyAccessServer) string {
+	if uri, ok := kasUris[kas.GetId()]; ok {
+		return uri
+	}
+	return kas.GetUri()
+}
+
+func exportValueFqn(valueFqns map[string]string, v *policy.Value) string {
+	if fqn, ok := valueFqns[v.GetId()]; ok {
+		return fqn
+	}
+	return v.GetFqn()
+}
+
+func sortManifest(m *manifest.Manifest) {
+	sort.SliceStable(m.Namespaces, func(i, j int) bool {
+		return m.Namespaces[i].Name < m.Namespaces[j].Name
+	})
+	sort.SliceStable(m.Attributes, func(i, j int) bool {
+		return GetAttributeFqn(m.Attributes[i].Namespace, m.Attributes[i].Name) < GetAttributeFqn(m.Attributes[j].Namespace, m.Attributes[j].Name)
+	})
+	sort.SliceStable(m.SubjectConditionSets, func(i, j int) bool {
+		return m.SubjectConditionSets[i].Name < m.SubjectConditionSets[j].Name
+	})
+	sort.SliceStable(m.SubjectMappings, func(i, j int) bool {
+		a, b := m.SubjectMappings[i], m.SubjectMappings[j]
+		if a.AttributeValue != b.AttributeValue {
+			return a.AttributeValue < b.AttributeValue
+		}
+		return a.SubjectConditionSet < b.SubjectConditionSet
+	})
+	sort.SliceStable(m.ResourceMappings, func(i, j int) bool {
+		return m.ResourceMappings[i].AttributeValue < m.ResourceMappings[j].AttributeValue
+	})
+	sort.SliceStable(m.KasRegistry, func(i, j int) bool {
+		return m.KasRegistry[i].Uri < m.KasRegistry[j].Uri
+	})
+	sort.SliceStable(m.KasGrants, func(i, j int) bool {
+		a, b := m.KasGrants[i], m.KasGrants[j]
+		if a.Attribute+a.Value != b.Attribute+b.Value {
+			return a.Attribute+a.Value < b.Attribute+b.Value
+		}
+		return a.Kas < b.Kas
+	})
+}
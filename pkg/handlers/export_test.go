@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestExportManifest(t *testing.T) {
+	kas := &kasregistry.KeyAccessServer{
+		Id:        "kas-1",
+		Uri:       "https://kas.example.com",
+		PublicKey: &kasregistry.PublicKey{PublicKey: &kasregistry.PublicKey_Remote{Remote: "https://kas.example.com/kas/v2/kas_public_key"}},
+	}
+	ns := &policy.Namespace{Id: "ns-1", Name: "example.com", Active: wrapperspb.Bool(true)}
+	top := &policy.Value{Id: "v-top", Value: "top", Active: wrapperspb.Bool(true)}
+	middle := &policy.Value{Id: "v-middle", Value: "middle", Active: wrapperspb.Bool(true)}
+	bottom := &policy.Value{Id: "v-bottom", Value: "bottom", Active: wrapperspb.Bool(true)}
+	retired := &policy.Value{Id: "v-retired", Value: "retired", Active: wrapperspb.Bool(false)}
+	attr := &policy.Attribute{
+		Id:        "attr-1",
+		Namespace: ns,
+		Name:      "level",
+		Rule:      policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY,
+		Values:    []*policy.Value{top, middle, bottom},
+		Active:    wrapperspb.Bool(true),
+	}
+	// only listed values carry their KAS grants
+	listedMiddle := &policy.Value{Id: "v-middle", Value: "middle", Active: wrapperspb.Bool(true), Grants: []*kasregistry.KeyAccessServer{kas}}
+
+	// two sets with the same content, one of them labeled
+	subjectSets := func() []*policy.SubjectSet {
+		return []*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{{
+			BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND,
+			Conditions: []*policy.Condition{{
+				SubjectExternalField:  ".department",
+				Operator:              policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN,
+				SubjectExternalValues: []string{"eng"},
+			}},
+		}}}}
+	}
+	scs1 := &policy.SubjectConditionSet{Id: "scs-1", SubjectSets: subjectSets()}
+	scs2 := &policy.SubjectConditionSet{Id: "scs-2", SubjectSets: subjectSets(), Metadata: &common.Metadata{Labels: map[string]string{"team": "a"}}}
+	action := []*policy.Action{{Value: &policy.Action_Custom{Custom: "read"}}}
+
+	p := exportedPolicy{
+		kasRegistry: []*kasregistry.KeyAccessServer{kas},
+		namespaces:  []*policy.Namespace{ns},
+		attributes:  []*policy.Attribute{attr},
+		values: map[string][]*policy.Value{
+			"attr-1": {bottom, retired, listedMiddle, top},
+		},
+		subjectConditionSets: []*policy.SubjectConditionSet{scs1, scs2},
+		subjectMappings: []*policy.SubjectMapping{
+			{Id: "sm-1", AttributeValue: top, SubjectConditionSet: scs1, Actions: action},
+			{Id: "sm-2", AttributeValue: bottom, SubjectConditionSet: scs2, Actions: action},
+		},
+	}
+
+	exported := exportManifest(p)
+	var buf bytes.Buffer
+	if err := manifest.Write(&buf, exported, manifest.FormatYAML); err != nil {
+		t.Fatal(err)
+	}
+	m, err := manifest.Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to parse the exported manifest: %v\n%s", err, buf.String())
+	}
+
+	var values []string
+	for _, v := range m.Attributes[0].Values {
+		values = append(values, v.Value)
+	}
+	if want := []string{"top", "middle", "bottom", "retired"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("expected values in the order of the attribute followed by inactive values %v, got %v", want, values)
+	}
+	if want := []manifest.KasGrant{{Kas: kas.Uri, Value: "https://example.com/attr/level/value/middle"}}; !reflect.DeepEqual(m.KasGrants, want) {
+		t.Errorf("expected the grants of the listed values %v, got %v", want, m.KasGrants)
+	}
+
+	name := SubjectConditionSetManifestName(SubjectSetsToManifest(subjectSets()))
+	if len(m.SubjectConditionSets) != 2 || m.SubjectConditionSets[0].Name != name || m.SubjectConditionSets[1].Name != name+"-2" {
+		t.Fatalf("expected subject condition sets %s and %s-2, got %+v", name, name, m.SubjectConditionSets)
+	}
+	if labels := m.SubjectConditionSets[1].Labels; labels["team"] != "a" {
+		t.Errorf("expected the labels of the second set to be kept, got %v", labels)
+	}
+	mappings := map[string]string{}
+	for _, sm := range m.SubjectMappings {
+		mappings[sm.AttributeValue] = sm.SubjectConditionSet
+	}
+	want := map[string]string{
+		"https://example.com/attr/level/value/top":    name,
+		"https://example.com/attr/level/value/bottom": name + "-2",
+	}
+	if !reflect.DeepEqual(mappings, want) {
+		t.Errorf("expected subject mappings %v, got %v", want, mappings)
+	}
+}
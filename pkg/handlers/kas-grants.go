@@ -9,9 +9,15 @@ func (h Handler) UpdateKasGrantForAttribute(attr_id string, kas_id string) (*att
 		AttributeId:       attr_id,
 		KeyAccessServerId: kas_id,
 	}
-	resp, err := h.sdk.Attributes.AssignKeyAccessServerToAttribute(h.ctx, &attributes.AssignKeyAccessServerToAttributeRequest{
+	req := &attributes.AssignKeyAccessServerToAttributeRequest{
 		AttributeKeyAccessServer: kas,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttribute(attr_id)
+		return nil, dryRun("AssignKeyAccessServerToAttribute", req, current, err)
+	}
+
+	resp, err := h.sdk.Attributes.AssignKeyAccessServerToAttribute(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -24,9 +30,15 @@ func (h Handler) DeleteKasGrantFromAttribute(attr_id string, kas_id string) (*at
 		AttributeId:       attr_id,
 		KeyAccessServerId: kas_id,
 	}
-	resp, err := h.sdk.Attributes.RemoveKeyAccessServerFromAttribute(h.ctx, &attributes.RemoveKeyAccessServerFromAttributeRequest{
+	req := &attributes.RemoveKeyAccessServerFromAttributeRequest{
 		AttributeKeyAccessServer: kas,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttribute(attr_id)
+		return nil, dryRun("RemoveKeyAccessServerFromAttribute", req, current, err)
+	}
+
+	resp, err := h.sdk.Attributes.RemoveKeyAccessServerFromAttribute(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -39,9 +51,15 @@ func (h Handler) UpdateKasGrantForValue(val_id string, kas_id string) (*attribut
 		ValueId:           val_id,
 		KeyAccessServerId: kas_id,
 	}
-	resp, err := h.sdk.Attributes.AssignKeyAccessServerToValue(h.ctx, &attributes.AssignKeyAccessServerToValueRequest{
+	req := &attributes.AssignKeyAccessServerToValueRequest{
 		ValueKeyAccessServer: kas,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttributeValue(val_id)
+		return nil, dryRun("AssignKeyAccessServerToValue", req, current, err)
+	}
+
+	resp, err := h.sdk.Attributes.AssignKeyAccessServerToValue(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +72,15 @@ func (h Handler) DeleteKasGrantFromValue(val_id string, kas_id string) (*attribu
 		ValueId:           val_id,
 		KeyAccessServerId: kas_id,
 	}
-	resp, err := h.sdk.Attributes.RemoveKeyAccessServerFromValue(h.ctx, &attributes.RemoveKeyAccessServerFromValueRequest{
+	req := &attributes.RemoveKeyAccessServerFromValueRequest{
 		ValueKeyAccessServer: kas,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetAttributeValue(val_id)
+		return nil, dryRun("RemoveKeyAccessServerFromValue", req, current, err)
+	}
+
+	resp, err := h.sdk.Attributes.RemoveKeyAccessServerFromValue(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
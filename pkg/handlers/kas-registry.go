@@ -32,6 +32,9 @@ func (h Handler) CreateKasRegistryEntry(uri string, publicKey *kasregistry.Publi
 		PublicKey: publicKey,
 		Metadata:  metadata,
 	}
+	if h.DryRun {
+		return nil, dryRun("CreateKeyAccessServer", req, nil, nil)
+	}
 
 	resp, err := h.sdk.KeyAccessServerRegistry.CreateKeyAccessServer(h.ctx, req)
 	if err != nil {
@@ -43,13 +46,19 @@ func (h Handler) CreateKasRegistryEntry(uri string, publicKey *kasregistry.Publi
 
 // Updates the KAS registry and then returns the KAS
 func (h Handler) UpdateKasRegistryEntry(id string, uri string, publickey *kasregistry.PublicKey, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*kasregistry.KeyAccessServer, error) {
-	_, err := h.sdk.KeyAccessServerRegistry.UpdateKeyAccessServer(h.ctx, &kasregistry.UpdateKeyAccessServerRequest{
+	req := &kasregistry.UpdateKeyAccessServerRequest{
 		Id:                     id,
 		Uri:                    uri,
 		PublicKey:              publickey,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetKasRegistryEntry(id)
+		return nil, dryRun("UpdateKeyAccessServer", req, current, err)
+	}
+
+	_, err := h.sdk.KeyAccessServerRegistry.UpdateKeyAccessServer(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +71,10 @@ func (h Handler) DeleteKasRegistryEntry(id string) (*kasregistry.KeyAccessServer
 	req := &kasregistry.DeleteKeyAccessServerRequest{
 		Id: id,
 	}
+	if h.DryRun {
+		current, err := h.GetKasRegistryEntry(id)
+		return nil, withDependents(dryRun("DeleteKeyAccessServer", req, current, err), h.KasRegistryEntryDependents, id)
+	}
 
 	resp, err := h.sdk.KeyAccessServerRegistry.DeleteKeyAccessServer(h.ctx, req)
 	if err != nil {
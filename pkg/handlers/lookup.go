@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Fqn is a parsed namespace, attribute or attribute value FQN, such as
+// https://example.com/attr/classification/value/secret
+type Fqn struct {
+	Namespace string
+	Attribute string
+	Value     string
+}
+
+func (f Fqn) String() string {
+	switch {
+	case f.Value != "":
+		return GetAttributeValueFqn(f.Namespace, f.Attribute, f.Value)
+	case f.Attribute != "":
+		return GetAttributeFqn(f.Namespace, f.Attribute)
+	}
+	return "https://" + f.Namespace
+}
+
+// IsUUID reports whether the reference is an object ID rather than a name or FQN
+func IsUUID(ref string) bool {
+	return uuidPattern.MatchString(ref)
+}
+
+// ParseFqn is the reverse of GetAttributeFqn and GetAttributeValueFqn, also accepting a namespace FQN
+// (https://example.com). Names are taken as they are, as the FQN functions do not escape them either.
+func ParseFqn(fqn string) (Fqn, error) {
+	invalid := fmt.Errorf("invalid FQN '%s', must be in the format https://<namespace>[/attr/<name>[/value/<value>]]", fqn)
+	rest, ok := strings.CutPrefix(fqn, "https://")
+	if !ok {
+		return Fqn{}, invalid
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	f := Fqn{Namespace: parts[0]}
+	if f.Namespace == "" {
+		return Fqn{}, invalid
+	}
+	switch len(parts) {
+	case 1:
+	case 3, 5:
+		if parts[1] != "attr" || parts[2] == "" {
+			return Fqn{}, invalid
+		}
+		f.Attribute = parts[2]
+		if len(parts) == 5 {
+			if parts[3] != "value" || parts[4] == "" {
+				return Fqn{}, invalid
+			}
+			f.Value = parts[4]
+		}
+	default:
+		return Fqn{}, invalid
+	}
+	return f, nil
+}
+
+// ResolveNamespaceId returns the ID of a namespace given by ID, name or FQN
+func (h Handler) ResolveNamespaceId(ref string) (string, error) {
+	if IsUUID(ref) {
+		return ref, nil
+	}
+	name := ref
+	if strings.HasPrefix(ref, "https://") {
+		f, err := ParseFqn(ref)
+		if err != nil {
+			return "", err
+		}
+		if f.Attribute != "" {
+			return "", fmt.Errorf("'%s' is an attribute FQN, not a namespace", ref)
+		}
+		name = f.Namespace
+	}
+	ns, err := h.findNamespace(name)
+	if err != nil {
+		return "", err
+	}
+	return ns.GetId(), nil
+}
+
+// ResolveAttributeId returns the ID of an attribute given by ID or FQN
+func (h Handler) ResolveAttributeId(ref string) (string, error) {
+	if IsUUID(ref) {
+		return ref, nil
+	}
+	f, err := ParseFqn(ref)
+	if err != nil {
+		return "", err
+	}
+	if f.Attribute == "" || f.Value != "" {
+		return "", fmt.Errorf("'%s' is not an attribute FQN, i.e. https://<namespace>/attr/<name>", ref)
+	}
+	attr, err := h.findAttribute(f)
+	if err != nil {
+		return "", err
+	}
+	return attr.GetId(), nil
+}
+
+// ResolveAttributeValueId returns the ID of an attribute value given by ID or FQN
+func (h Handler) ResolveAttributeValueId(ref string) (string, error) {
+	if IsUUID(ref) {
+		return ref, nil
+	}
+	f, err := ParseFqn(ref)
+	if err != nil {
+		return "", err
+	}
+	if f.Value == "" {
+		return "", fmt.Errorf("'%s' is not an attribute value FQN, i.e. https://<namespace>/attr/<name>/value/<value>", ref)
+	}
+	attr, err := h.findAttribute(f)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range attr.GetValues() {
+		if strings.EqualFold(v.GetValue(), f.Value) {
+			return v.GetId(), nil
+		}
+	}
+	return "", status.Errorf(codes.NotFound, "attribute value %s not found", f)
+}
+
+// ResolveKasRegistryEntryId returns the ID of a KAS registry entry given by ID or URI
+func (h Handler) ResolveKasRegistryEntryId(ref string) (string, error) {
+	if IsUUID(ref) {
+		return ref, nil
+	}
+	list, err := h.ListKasRegistryEntries()
+	if err != nil {
+		return "", err
+	}
+	for _, kas := range list {
+		if strings.EqualFold(strings.TrimSuffix(kas.GetUri(), "/"), strings.TrimSuffix(ref, "/")) {
+			return kas.GetId(), nil
+		}
+	}
+	return "", status.Errorf(codes.NotFound, "KAS registry entry %s not found", ref)
+}
+
+// Names are matched ignoring case, the same as FQNs are compared when applying a manifest
+func (h Handler) findNamespace(name string) (*policy.Namespace, error) {
+	list, err := h.ListNamespaces(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range list {
+		if strings.EqualFold(ns.GetName(), name) {
+			return ns, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "namespace %s not found", name)
+}
+
+func (h Handler) findAttribute(f Fqn) (*policy.Attribute, error) {
+	attrs, err := h.ListAttributes(common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.GetNamespace().GetName(), f.Namespace) && strings.EqualFold(attr.GetName(), f.Attribute) {
+			return attr, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "attribute %s not found", GetAttributeFqn(f.Namespace, f.Attribute))
+}
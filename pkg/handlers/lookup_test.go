@@ -0,0 +1,98 @@
+package handlers
+
+import "testing"
+
+func TestParseFqn(t *testing.T) {
+	tests := []struct {
+		name string
+		fqn  string
+		want Fqn
+	}{
+		{name: "namespace", fqn: "https://example.com", want: Fqn{Namespace: "example.com"}},
+		{name: "attribute", fqn: "https://example.com/attr/level", want: Fqn{Namespace: "example.com", Attribute: "level"}},
+		{name: "value", fqn: "https://example.com/attr/level/value/top-secret", want: Fqn{Namespace: "example.com", Attribute: "level", Value: "top-secret"}},
+		{name: "value with a percent sign", fqn: "https://example.com/attr/level/value/100%25", want: Fqn{Namespace: "example.com", Attribute: "level", Value: "100%25"}},
+		{name: "names in any case", fqn: "https://Example.com/attr/Level/value/High", want: Fqn{Namespace: "Example.com", Attribute: "Level", Value: "High"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFqn(tt.fqn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+			if got.String() != tt.fqn {
+				t.Errorf("expected %+v to format back as %s, got %s", got, tt.fqn, got.String())
+			}
+		})
+	}
+
+	t.Run("trailing slash", func(t *testing.T) {
+		got, err := ParseFqn("https://example.com/attr/level/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (Fqn{Namespace: "example.com", Attribute: "level"}); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+}
+
+func TestParseFqnRoundTrip(t *testing.T) {
+	values := []string{"secret", "top_secret", "TS-1", "100%", "a%2Fb", "café"}
+	for _, v := range values {
+		t.Run(v, func(t *testing.T) {
+			fqn := GetAttributeValueFqn("example.com", "level", v)
+			got, err := ParseFqn(fqn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (Fqn{Namespace: "example.com", Attribute: "level", Value: v}); got != want {
+				t.Errorf("expected %s to parse into %+v, got %+v", fqn, want, got)
+			}
+		})
+	}
+}
+
+func TestParseFqnInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"example.com",
+		"http://example.com/attr/level",
+		"https://",
+		"https:///attr/level",
+		"https://example.com/attr",
+		"https://example.com/attr/",
+		"https://example.com/attribute/level",
+		"https://example.com/attr/level/value",
+		"https://example.com/attr/level/value/",
+		"https://example.com/attr/level/values/high",
+		"https://example.com/attr/level/value/high/extra",
+		"https://example.com/attr/level/value/a/b",
+	}
+	for _, fqn := range invalid {
+		t.Run(fqn, func(t *testing.T) {
+			if got, err := ParseFqn(fqn); err == nil {
+				t.Errorf("expected an error, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestIsUUID(t *testing.T) {
+	tests := map[string]bool{
+		"3b1f4c2e-8d9a-4f5b-a6c7-0e1d2f3a4b5c": true,
+		"3B1F4C2E-8D9A-4F5B-A6C7-0E1D2F3A4B5C": true,
+		"3b1f4c2e8d9a4f5ba6c70e1d2f3a4b5c":     false,
+		"example.com":                          false,
+		"https://example.com/attr/level":       false,
+	}
+	for ref, want := range tests {
+		if got := IsUUID(ref); got != want {
+			t.Errorf("expected IsUUID(%q) to be %t, got %t", ref, want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/manifest"
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// Converts manifest subject sets into the policy objects expected by the platform
+func SubjectSetsFromManifest(sets []manifest.SubjectSet) ([]*policy.SubjectSet, error) {
+	ss := make([]*policy.SubjectSet, len(sets))
+	for i, s := range sets {
+		groups := make([]*policy.ConditionGroup, len(s.ConditionGroups))
+		for j, g := range s.ConditionGroups {
+			// the enum names of the json output are accepted as well as the readable choices
+			boolType := GetConditionBooleanTypeFromChoice(strings.TrimPrefix(strings.ToUpper(g.BooleanOperator), "CONDITION_BOOLEAN_TYPE_ENUM_"))
+			if boolType == policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_UNSPECIFIED {
+				return nil, fmt.Errorf("invalid boolean operator %q: must be one of [%s, %s]", g.BooleanOperator, ConditionBooleanTypeAnd, ConditionBooleanTypeOr)
+			}
+			conditions := make([]*policy.Condition, len(g.Conditions))
+			for k, c := range g.Conditions {
+				op := GetSubjectMappingOperatorFromChoice(strings.TrimPrefix(strings.ToUpper(c.Operator), "SUBJECT_MAPPING_OPERATOR_ENUM_"))
+				if op == policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_UNSPECIFIED {
+					return nil, fmt.Errorf("invalid operator %q: must be one of [%s, %s]", c.Operator, SubjectMappingOperatorIn, SubjectMappingOperatorNotIn)
+				}
+				conditions[k] = &policy.Condition{
+					SubjectExternalField:  c.SubjectExternalField,
+					Operator:              op,
+					SubjectExternalValues: c.SubjectExternalValues,
+				}
+			}
+			groups[j] = &policy.ConditionGroup{
+				Conditions:      conditions,
+				BooleanOperator: boolType,
+			}
+		}
+		ss[i] = &policy.SubjectSet{ConditionGroups: groups}
+	}
+	return ss, nil
+}
+
+// Converts platform subject sets into their readable manifest form
+func SubjectSetsToManifest(ss []*policy.SubjectSet) []manifest.SubjectSet {
+	sets := make([]manifest.SubjectSet, len(ss))
+	for i, s := range ss {
+		groups := make([]manifest.ConditionGroup, len(s.GetConditionGroups()))
+		for j, g := range s.GetConditionGroups() {
+			conditions := make([]manifest.Condition, len(g.GetConditions()))
+			for k, c := range g.GetConditions() {
+				conditions[k] = manifest.Condition{
+					SubjectExternalField:  c.GetSubjectExternalField(),
+					Operator:              GetSubjectMappingOperatorChoiceFromEnum(c.GetOperator()),
+					SubjectExternalValues: c.GetSubjectExternalValues(),
+				}
+			}
+			groups[j] = manifest.ConditionGroup{
+				BooleanOperator: GetConditionBooleanTypeChoiceFromEnum(g.GetBooleanOperator()),
+				Conditions:      conditions,
+			}
+		}
+		sets[i] = manifest.SubjectSet{ConditionGroups: groups}
+	}
+	return sets
+}
+
+// Converts platform actions into their readable manifest form, sorted for stable comparison
+func ActionsToManifest(actions []*policy.Action) manifest.Actions {
+	a := manifest.Actions{}
+	for _, action := range actions {
+		if action.GetCustom() != "" {
+			a.Custom = append(a.Custom, action.GetCustom())
+		} else if std := GetSubjectMappingActionChoiceFromEnum(action.GetStandard()); std != "" {
+			a.Standard = append(a.Standard, std)
+		}
+	}
+	sort.Strings(a.Standard)
+	sort.Strings(a.Custom)
+	return a
+}
+
+func labelsMetadata(labels map[string]string) *common.MetadataMutable {
+	if labels == nil {
+		return nil
+	}
+	return &common.MetadataMutable{Labels: labels}
+}
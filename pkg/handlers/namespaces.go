@@ -28,10 +28,15 @@ func (h Handler) ListNamespaces(state common.ActiveStateEnum) ([]*policy.Namespa
 
 // Creates and returns the created n
 func (h Handler) CreateNamespace(name string, metadata *common.MetadataMutable) (*policy.Namespace, error) {
-	resp, err := h.sdk.Namespaces.CreateNamespace(h.ctx, &namespaces.CreateNamespaceRequest{
+	req := &namespaces.CreateNamespaceRequest{
 		Name:     name,
 		Metadata: metadata,
-	})
+	}
+	if h.DryRun {
+		return nil, dryRun("CreateNamespace", req, nil, nil)
+	}
+
+	resp, err := h.sdk.Namespaces.CreateNamespace(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +46,17 @@ func (h Handler) CreateNamespace(name string, metadata *common.MetadataMutable)
 
 // Updates and returns the updated namespace
 func (h Handler) UpdateNamespace(id string, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.Namespace, error) {
-	_, err := h.sdk.Namespaces.UpdateNamespace(h.ctx, &namespaces.UpdateNamespaceRequest{
+	req := &namespaces.UpdateNamespaceRequest{
 		Id:                     id,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetNamespace(id)
+		return nil, dryRun("UpdateNamespace", req, current, err)
+	}
+
+	_, err := h.sdk.Namespaces.UpdateNamespace(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +65,15 @@ func (h Handler) UpdateNamespace(id string, metadata *common.MetadataMutable, be
 
 // Deactivates and returns the deactivated namespace
 func (h Handler) DeactivateNamespace(id string) (*policy.Namespace, error) {
-	_, err := h.sdk.Namespaces.DeactivateNamespace(h.ctx, &namespaces.DeactivateNamespaceRequest{
+	req := &namespaces.DeactivateNamespaceRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetNamespace(id)
+		return nil, withDependents(dryRun("DeactivateNamespace", req, current, err), h.NamespaceDependents, id)
+	}
+
+	_, err := h.sdk.Namespaces.DeactivateNamespace(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"regexp"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+type TermMatch struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// ResourceMappingMatch is a resource mapping with at least one term found in the text
+type ResourceMappingMatch struct {
+	ResourceMappingId string      `json:"resourceMappingId"`
+	AttributeValueId  string      `json:"attributeValueId"`
+	AttributeValueFqn string      `json:"attributeValueFqn"`
+	MatchedTerms      []TermMatch `json:"matchedTerms"`
+}
+
+// ResourceMappingMatches are the attribute values a tagging PDP would apply to a text, and the mappings that matched
+type ResourceMappingMatches struct {
+	AttributeValueFqns []string               `json:"attributeValueFqns"`
+	Matches            []ResourceMappingMatch `json:"resourceMappings"`
+}
+
+// MatchResourceMappings finds the terms of every resource mapping in the text, ignoring case and only as whole
+// words, so 'secret' matches 'Top SECRET.' but not 'secretary'
+func (h *Handler) MatchResourceMappings(text string) (*ResourceMappingMatches, error) {
+	mappings, err := h.ListResourceMappings()
+	if err != nil {
+		return nil, err
+	}
+	fqns, err := h.attributeValueFqns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResourceMappingMatches{AttributeValueFqns: []string{}, Matches: []ResourceMappingMatch{}}
+	tagged := map[string]bool{}
+	for _, rm := range mappings {
+		match := ResourceMappingMatch{
+			ResourceMappingId: rm.GetId(),
+			AttributeValueId:  rm.GetAttributeValue().GetId(),
+			AttributeValueFqn: fqns[rm.GetAttributeValue().GetId()],
+		}
+		if match.AttributeValueFqn == "" {
+			match.AttributeValueFqn = rm.GetAttributeValue().GetValue()
+		}
+		for _, term := range rm.GetTerms() {
+			if n := countTerm(text, term); n > 0 {
+				match.MatchedTerms = append(match.MatchedTerms, TermMatch{Term: term, Count: n})
+			}
+		}
+		if len(match.MatchedTerms) == 0 {
+			continue
+		}
+		result.Matches = append(result.Matches, match)
+		if !tagged[match.AttributeValueFqn] {
+			tagged[match.AttributeValueFqn] = true
+			result.AttributeValueFqns = append(result.AttributeValueFqns, match.AttributeValueFqn)
+		}
+	}
+	sort.Strings(result.AttributeValueFqns)
+	return result, nil
+}
+
+// Counts the case-insensitive occurrences of the term that are not part of a longer word. Boundaries are checked
+// around the term rather than with \b, so terms starting or ending with punctuation, such as 'c++', match too.
+func countTerm(text string, term string) int {
+	if term == "" {
+		return 0
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+	count := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if !isWordRune(before) && !isWordRune(after) {
+			count++
+		}
+	}
+	return count
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import "testing"
+
+func TestCountTerm(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		term string
+		want int
+	}{
+		{name: "whole word", text: "this is secret", term: "secret", want: 1},
+		{name: "not within a longer word", text: "ask the secretary", term: "secret", want: 0},
+		{name: "not at the end of a longer word", text: "topsecret", term: "secret", want: 0},
+		{name: "digits and underscores are part of words", text: "secret2 secret_plan 2secret", term: "secret", want: 0},
+		{name: "punctuation is a boundary", text: "Top SECRET. (secret) secret-ish \"secret\"", term: "secret", want: 4},
+		{name: "case folded", text: "Secret SECRET sEcReT", term: "SECRET", want: 3},
+		{name: "every occurrence", text: "secret secretary secret", term: "secret", want: 2},
+		{name: "phrase", text: "this is Top  Secret, not top secret", term: "top secret", want: 1},
+		{name: "term ending in punctuation", text: "written in C++ and c++.", term: "c++", want: 2},
+		{name: "term ending in punctuation within a word", text: "c++x", term: "c++", want: 0},
+		{name: "term starting with punctuation", text: "see .NET, not a.net", term: ".net", want: 1},
+		{name: "regexp characters are literal", text: "costs $5 (approx.) or 5x5", term: "(approx.)", want: 1},
+		{name: "non-ASCII letters are word characters", text: "secretário secretária secret", term: "secret", want: 1},
+		{name: "non-ASCII term", text: "Das ist GEHEIM und streng geheim", term: "geheim", want: 2},
+		{name: "non-ASCII case folding", text: "ÉTÉ été Été", term: "été", want: 3},
+		{name: "non-Latin scripts", text: "совершенно СЕКРЕТНО, секретно", term: "секретно", want: 2},
+		{name: "non-Latin word boundaries", text: "секретность", term: "секрет", want: 0},
+		// only simple case folding applies, so 'ß' does not match 'SS'
+		{name: "full case folding is not applied", text: "STRASSE", term: "straße", want: 0},
+		{name: "invalid UTF-8 is a boundary", text: "\xffsecret\xff", term: "secret", want: 1},
+		{name: "empty term", text: "secret", term: "", want: 0},
+		{name: "empty text", text: "", term: "secret", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countTerm(tt.text, tt.term); got != tt.want {
+				t.Errorf("expected %d occurrences of %q in %q, got %d", tt.want, tt.term, tt.text, got)
+			}
+		})
+	}
+}
@@ -16,11 +16,16 @@ type ResourceMapping struct {
 
 // Creates and returns the created resource mapping
 func (h *Handler) CreateResourceMapping(attributeId string, terms []string, metadata *common.MetadataMutable) (*policy.ResourceMapping, error) {
-	res, err := h.sdk.ResourceMapping.CreateResourceMapping(context.Background(), &resourcemapping.CreateResourceMappingRequest{
+	req := &resourcemapping.CreateResourceMappingRequest{
 		AttributeValueId: attributeId,
 		Terms:            terms,
 		Metadata:         metadata,
-	})
+	}
+	if h.DryRun {
+		return nil, dryRun("CreateResourceMapping", req, nil, nil)
+	}
+
+	res, err := h.sdk.ResourceMapping.CreateResourceMapping(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +56,19 @@ func (h *Handler) ListResourceMappings() ([]*policy.ResourceMapping, error) {
 // TODO: verify updation behavior
 // Updates and returns the updated resource mapping
 func (h *Handler) UpdateResourceMapping(id string, attrValueId string, terms []string, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.ResourceMapping, error) {
-	_, err := h.sdk.ResourceMapping.UpdateResourceMapping(context.Background(), &resourcemapping.UpdateResourceMappingRequest{
+	req := &resourcemapping.UpdateResourceMappingRequest{
 		Id:                     id,
 		AttributeValueId:       attrValueId,
 		Terms:                  terms,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetResourceMapping(id)
+		return nil, dryRun("UpdateResourceMapping", req, current, err)
+	}
+
+	_, err := h.sdk.ResourceMapping.UpdateResourceMapping(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -66,9 +77,15 @@ func (h *Handler) UpdateResourceMapping(id string, attrValueId string, terms []s
 }
 
 func (h *Handler) DeleteResourceMapping(id string) (*policy.ResourceMapping, error) {
-	resp, err := h.sdk.ResourceMapping.DeleteResourceMapping(context.Background(), &resourcemapping.DeleteResourceMappingRequest{
+	req := &resourcemapping.DeleteResourceMappingRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetResourceMapping(id)
+		return nil, dryRun("DeleteResourceMapping", req, current, err)
+	}
+
+	resp, err := h.sdk.ResourceMapping.DeleteResourceMapping(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 
+	"github.com/opentdf/otdfctl/pkg/grpc"
 	"github.com/opentdf/platform/protocol/go/common"
 	"github.com/opentdf/platform/sdk"
 )
@@ -13,35 +14,52 @@ type Handler struct {
 	sdk        *sdk.SDK
 	ctx        context.Context
 	OIDC_TOKEN string
+
+	// the platform connected to, which cached logins and IdP discovery are keyed on
+	platformEndpoint string
+	tlsConfig        grpc.TLSConfig
+	// the subject RPCs are authenticated as, when not the logged in one
+	actAs string
+
+	// When set, mutating handlers return a *DryRunError instead of calling the RPC
+	DryRun bool
 }
 
-func New(platformEndpoint string) (Handler, error) {
-	// define the scopes in an array
-	// scopes := []string{"email"}
-	// normally, we should try to retrieve an active OICD token here, however, the SDK has no option for passing a token
-	// so instead, we'll check if we have a clientId and clientSecret stored, and if so, we'll use those to init the SDK, otherwise, we'll use the insecure connection (which will stop working once we enforce auth on the backend)
-	// clientSecret, clientId, err := GetClientIdAndSecretFromCache()
+// New connects to the platform. When actAs names a subject, every RPC is authenticated as that subject by
+// exchanging the cached login's token.
+func New(platformEndpoint string, tlsConfig grpc.TLSConfig, actAs string) (Handler, error) {
+	tlsCfg, err := tlsConfig.Load()
+	if err != nil {
+		return Handler{}, err
+	}
+	transport := sdk.WithInsecureConn()
+	if tlsCfg != nil {
+		transport = sdk.WithTLSCredentials(tlsCfg, nil)
+	}
 
-	// if err != nil {
-	// 	return Handler{}, err
-	// }
+	ctx := context.Background()
+	opts := []sdk.Option{transport}
+	// once logged in with 'auth login', every connection is authenticated with the cached token or client
+	// credentials; otherwise the connection is unauthenticated (which stops working once the platform enforces auth)
+	auth, err := cachedAuthOption(ctx, platformEndpoint, tlsCfg != nil, actAs)
+	if err != nil {
+		return Handler{}, err
+	}
+	if auth != nil {
+		opts = append(opts, auth)
+	}
 
-	// scopes := []string{"email"}
-	// create the sdk with the client credentials
-	// NOTE FROM AVERY: The below line is commented out because although it should work, the SDK
-	// is having trouble with the "WithClientCredentials" endpoint
-	// so although the commented out line should work, and will work in the future, today it doesn't, so
-	// to facilitate development, we're leaving it commented, until the SDK is fixed, and using the insecure connection instead
-	// note that for now we're hard coding the TOKEN_URL until we have an endpoint to get the config from
-	// sdk, err := sdk.New(platformEndpoint, sdk.WithClientCredentials(clientId, clientSecret, scopes), sdk.WithTokenEndpoint(TOKEN_URL))
-	sdk, err := sdk.New(platformEndpoint, sdk.WithInsecureConn())
+	sdk, err := sdk.New(platformEndpoint, opts...)
 	if err != nil {
 		return Handler{}, err
 	}
 
 	return Handler{
-		sdk: sdk,
-		ctx: context.Background(),
+		sdk:              sdk,
+		ctx:              ctx,
+		platformEndpoint: platformEndpoint,
+		tlsConfig:        tlsConfig,
+		actAs:            actAs,
 	}, nil
 }
 
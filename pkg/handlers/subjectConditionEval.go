@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// ConditionEvaluation is the outcome of one condition against the values its selector found in the entity
+type ConditionEvaluation struct {
+	Selector     string   `json:"selector"`
+	Operator     string   `json:"operator"`
+	Values       []string `json:"values"`
+	EntityValues []string `json:"entityValues"`
+	Matched      bool     `json:"matched"`
+	Error        string   `json:"error,omitempty"`
+}
+
+type ConditionGroupEvaluation struct {
+	BooleanOperator string                `json:"booleanOperator"`
+	Conditions      []ConditionEvaluation `json:"conditions"`
+	Matched         bool                  `json:"matched"`
+	Error           string                `json:"error,omitempty"`
+}
+
+type SubjectSetEvaluation struct {
+	ConditionGroups []ConditionGroupEvaluation `json:"conditionGroups"`
+	Matched         bool                       `json:"matched"`
+}
+
+// SubjectConditionSetEvaluation explains whether an entity matches a subject condition set. Like the platform, all
+// subject sets and all of their condition groups must match, while the conditions of a group are joined by its
+// boolean operator. A missing or empty condition set matches no entity.
+type SubjectConditionSetEvaluation struct {
+	Id          string                 `json:"id,omitempty"`
+	SubjectSets []SubjectSetEvaluation `json:"subjectSets"`
+	Matched     bool                   `json:"matched"`
+	// why the condition set matched no entity, when it has nothing to evaluate
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// LoadEntity reads a JSON document of entity claims, such as the claims of an access token or an entity from the
+// IdP, for the client side evaluation of subject condition sets
+func LoadEntity(path string) (map[string]interface{}, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entity map[string]interface{}
+	if err := json.Unmarshal(b, &entity); err != nil {
+		return nil, fmt.Errorf("entity must be a JSON object: %w", err)
+	}
+	return entity, nil
+}
+
+// EvaluateSubjectConditionSet evaluates the subject sets of a condition set against the entity claims on the
+// client, without calling the platform
+func EvaluateSubjectConditionSet(scs *policy.SubjectConditionSet, entity map[string]interface{}) SubjectConditionSetEvaluation {
+	result := SubjectConditionSetEvaluation{Id: scs.GetId(), SubjectSets: []SubjectSetEvaluation{}}
+	switch {
+	case scs == nil:
+		result.Explanation = "there is no subject condition set, so no entity matches"
+		return result
+	case len(scs.GetSubjectSets()) == 0:
+		result.Explanation = "the subject condition set has no subject sets, so no entity matches"
+		return result
+	}
+
+	result.Matched = true
+	for _, ss := range scs.GetSubjectSets() {
+		setResult := SubjectSetEvaluation{Matched: true}
+		for _, g := range ss.GetConditionGroups() {
+			groupResult := evaluateConditionGroup(g, entity)
+			setResult.ConditionGroups = append(setResult.ConditionGroups, groupResult)
+			setResult.Matched = setResult.Matched && groupResult.Matched
+		}
+		result.SubjectSets = append(result.SubjectSets, setResult)
+		result.Matched = result.Matched && setResult.Matched
+	}
+	return result
+}
+
+func evaluateConditionGroup(g *policy.ConditionGroup, entity map[string]interface{}) ConditionGroupEvaluation {
+	op := GetConditionBooleanTypeChoiceFromEnum(g.GetBooleanOperator())
+	result := ConditionGroupEvaluation{BooleanOperator: op, Matched: op != ConditionBooleanTypeOr}
+	for _, c := range g.GetConditions() {
+		cr := evaluateCondition(c, entity)
+		result.Conditions = append(result.Conditions, cr)
+		if op == ConditionBooleanTypeOr {
+			result.Matched = result.Matched || cr.Matched
+		} else {
+			result.Matched = result.Matched && cr.Matched
+		}
+	}
+	// the conditions are still evaluated to explain them, but there is no telling how they combine
+	if op == "" {
+		result.Matched = false
+		result.Error = "unspecified boolean operator"
+	}
+	return result
+}
+
+// IN matches when any value found by the selector is one of the condition values, NOT_IN when none is, including
+// when the selector finds nothing
+func evaluateCondition(c *policy.Condition, entity map[string]interface{}) ConditionEvaluation {
+	result := ConditionEvaluation{
+		Selector:     c.GetSubjectExternalField(),
+		Operator:     GetSubjectMappingOperatorChoiceFromEnum(c.GetOperator()),
+		Values:       c.GetSubjectExternalValues(),
+		EntityValues: []string{},
+	}
+	found, err := SelectEntityValues(entity, result.Selector)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.EntityValues = found
+
+	in := false
+	for _, v := range found {
+		for _, want := range result.Values {
+			if v == want {
+				in = true
+			}
+		}
+	}
+	switch c.GetOperator() {
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN:
+		result.Matched = in
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN:
+		result.Matched = !in
+	default:
+		result.Error = "unspecified operator"
+	}
+	return result
+}
+
+// SelectEntityValues returns the values a subject external selector finds in the entity claims. Selectors are
+// paths such as '.org.department', '.groups[]' or '.emails[0]', where keys with punctuation are quoted as
+// '."key.with.dots"' or '["key"]'. Arrays are flattened, and numbers and booleans are compared as their text.
+func SelectEntityValues(entity map[string]interface{}, selector string) ([]string, error) {
+	path := strings.TrimSpace(selector)
+	if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "[") {
+		return nil, fmt.Errorf("invalid selector '%s', must start with '.'", selector)
+	}
+
+	nodes := []interface{}{entity}
+	for path != "" {
+		var key string
+		switch {
+		case strings.HasPrefix(path, `."`) || strings.HasPrefix(path, `["`):
+			closing := `"`
+			if path[0] == '[' {
+				closing = `"]`
+			}
+			end := strings.Index(path[2:], closing)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid selector '%s': unterminated quoted key", selector)
+			}
+			key, path = path[2:2+end], path[2+end+len(closing):]
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid selector '%s': unclosed '['", selector)
+			}
+			index := strings.TrimSpace(path[1:end])
+			path = path[end+1:]
+			next := []interface{}{}
+			for _, n := range nodes {
+				l, ok := n.([]interface{})
+				if !ok {
+					continue
+				}
+				if index == "" {
+					next = append(next, l...)
+					continue
+				}
+				i, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, fmt.Errorf("invalid selector '%s': '[%s]' is not an index", selector, index)
+				}
+				if i < 0 {
+					i += len(l)
+				}
+				if i >= 0 && i < len(l) {
+					next = append(next, l[i])
+				}
+			}
+			nodes = next
+			continue
+		case strings.HasPrefix(path, "."):
+			end := strings.IndexAny(path[1:], ".[")
+			if end < 0 {
+				key, path = path[1:], ""
+			} else {
+				key, path = path[1:1+end], path[1+end:]
+			}
+			if key == "" {
+				if path != "" && !strings.HasPrefix(path, "[") {
+					return nil, fmt.Errorf("invalid selector '%s': empty key", selector)
+				}
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("invalid selector '%s': unexpected '%s'", selector, path)
+		}
+
+		next := []interface{}{}
+		for _, n := range nodes {
+			if m, ok := n.(map[string]interface{}); ok {
+				if v, ok := m[key]; ok {
+					next = append(next, v)
+				}
+			}
+		}
+		nodes = next
+	}
+
+	values := []string{}
+	for _, n := range nodes {
+		values = append(values, entityScalars(n)...)
+	}
+	return values, nil
+}
+
+// arrays are flattened into their scalars, objects have no value to compare
+func entityScalars(n interface{}) []string {
+	switch v := n.(type) {
+	case nil, map[string]interface{}:
+		return nil
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			values = append(values, entityScalars(item)...)
+		}
+		return values
+	}
+	return []string{fmt.Sprint(n)}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+const testEntity = `{
+	"sub": "jane",
+	"email_verified": true,
+	"level": 3,
+	"org": {"department": "eng", "site": null},
+	"groups": ["eng", "ops", ["nested"]],
+	"emails": [{"address": "jane@example.com"}, {"address": "j@example.com"}],
+	"key.with.dots": "dotted",
+	"realm_access": {"roles": ["admin", "user"]}
+}`
+
+func loadTestEntity(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var entity map[string]interface{}
+	if err := json.Unmarshal([]byte(testEntity), &entity); err != nil {
+		t.Fatal(err)
+	}
+	return entity
+}
+
+func TestSelectEntityValues(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+		// the expected error, where empty is success
+		err string
+	}{
+		{selector: ".sub", want: []string{"jane"}},
+		{selector: " .sub ", want: []string{"jane"}},
+		{selector: ".org.department", want: []string{"eng"}},
+		{selector: ".email_verified", want: []string{"true"}},
+		{selector: ".level", want: []string{"3"}},
+		{selector: ".groups", want: []string{"eng", "ops", "nested"}},
+		{selector: ".groups[]", want: []string{"eng", "ops", "nested"}},
+		{selector: ".groups[1]", want: []string{"ops"}},
+		{selector: ".groups[-1]", want: []string{"nested"}},
+		{selector: ".groups[5]", want: []string{}},
+		{selector: ".emails[].address", want: []string{"jane@example.com", "j@example.com"}},
+		{selector: ".emails[0].address", want: []string{"jane@example.com"}},
+		{selector: `."key.with.dots"`, want: []string{"dotted"}},
+		{selector: `["key.with.dots"]`, want: []string{"dotted"}},
+		{selector: `.realm_access["roles"][]`, want: []string{"admin", "user"}},
+		{selector: ".org", want: []string{}},
+		{selector: ".org.site", want: []string{}},
+		{selector: ".missing.key", want: []string{}},
+		{selector: ".sub[]", want: []string{}},
+		{selector: "sub", err: "must start with '.'"},
+		{selector: `."sub`, err: "unterminated quoted key"},
+		{selector: ".groups[0", err: "unclosed '['"},
+		{selector: ".groups[first]", err: "is not an index"},
+		{selector: ".org..department", err: "empty key"},
+	}
+
+	entity := loadTestEntity(t)
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := SelectEntityValues(entity, tt.selector)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v (%v)", tt.err, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateSubjectConditionSet(t *testing.T) {
+	condition := func(selector string, op policy.SubjectMappingOperatorEnum, values ...string) *policy.Condition {
+		return &policy.Condition{SubjectExternalField: selector, Operator: op, SubjectExternalValues: values}
+	}
+	inOp := policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN
+	notInOp := policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN
+	andOp := policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND
+	orOp := policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+	unspecified := policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_UNSPECIFIED
+
+	tests := []struct {
+		name   string
+		groups []*policy.ConditionGroup
+		want   bool
+		// the expected error of the first group and of its first condition
+		groupErr     string
+		conditionErr string
+	}{
+		{
+			name:   "AND of matching conditions",
+			groups: []*policy.ConditionGroup{{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".org.department", inOp, "eng"), condition(".groups", notInOp, "sales")}}},
+			want:   true,
+		},
+		{
+			name:   "AND with a condition that does not match",
+			groups: []*policy.ConditionGroup{{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".org.department", inOp, "eng"), condition(".groups", inOp, "sales")}}},
+		},
+		{
+			name:   "OR with one matching condition",
+			groups: []*policy.ConditionGroup{{BooleanOperator: orOp, Conditions: []*policy.Condition{condition(".sub", inOp, "joe"), condition(".level", inOp, "3")}}},
+			want:   true,
+		},
+		{
+			name:   "NOT_IN of a missing claim",
+			groups: []*policy.ConditionGroup{{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".missing", notInOp, "x")}}},
+			want:   true,
+		},
+		{
+			name: "every group must match",
+			groups: []*policy.ConditionGroup{
+				{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".sub", inOp, "jane")}},
+				{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".sub", inOp, "joe")}},
+			},
+		},
+		{
+			name:         "invalid selector",
+			groups:       []*policy.ConditionGroup{{BooleanOperator: orOp, Conditions: []*policy.Condition{condition("sub", inOp, "jane")}}},
+			conditionErr: "must start with '.'",
+		},
+		{
+			name:         "unspecified operator",
+			groups:       []*policy.ConditionGroup{{BooleanOperator: andOp, Conditions: []*policy.Condition{condition(".sub", policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_UNSPECIFIED, "jane")}}},
+			conditionErr: "unspecified operator",
+		},
+		{
+			name:     "unspecified boolean operator",
+			groups:   []*policy.ConditionGroup{{BooleanOperator: unspecified, Conditions: []*policy.Condition{condition(".sub", inOp, "jane")}}},
+			groupErr: "unspecified boolean operator",
+		},
+	}
+
+	entity := loadTestEntity(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scs := &policy.SubjectConditionSet{Id: "scs", SubjectSets: []*policy.SubjectSet{{ConditionGroups: tt.groups}}}
+			result := EvaluateSubjectConditionSet(scs, entity)
+			if result.Matched != tt.want {
+				t.Errorf("expected matched %t, got %t", tt.want, result.Matched)
+			}
+			if len(result.SubjectSets) != 1 || len(result.SubjectSets[0].ConditionGroups) != len(tt.groups) {
+				t.Fatalf("expected an evaluation of every group, got %+v", result)
+			}
+			group := result.SubjectSets[0].ConditionGroups[0]
+			if group.Error != tt.groupErr {
+				t.Errorf("expected group error %q, got %q", tt.groupErr, group.Error)
+			}
+			if len(group.Conditions) != len(tt.groups[0].GetConditions()) {
+				t.Fatalf("expected an evaluation of every condition, got %+v", group)
+			}
+			if err := group.Conditions[0].Error; (tt.conditionErr == "") != (err == "") || !strings.Contains(err, tt.conditionErr) {
+				t.Errorf("expected condition error %q, got %q", tt.conditionErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluateEmptySubjectConditionSet(t *testing.T) {
+	tests := []struct {
+		name string
+		scs  *policy.SubjectConditionSet
+	}{
+		{name: "missing condition set"},
+		{name: "condition set without subject sets", scs: &policy.SubjectConditionSet{Id: "scs"}},
+		{name: "condition set with empty subject sets", scs: &policy.SubjectConditionSet{Id: "scs", SubjectSets: []*policy.SubjectSet{}}},
+	}
+
+	entity := loadTestEntity(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateSubjectConditionSet(tt.scs, entity)
+			if result.Matched {
+				t.Error("expected no entity to match")
+			}
+			if result.Explanation == "" {
+				t.Error("expected an explanation of the non-match")
+			}
+		})
+	}
+}
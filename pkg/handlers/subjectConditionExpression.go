@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// Condition expressions are a compact form of subject sets:
+//
+//	.org.department IN [eng, ops] AND .role NOT_IN [contractor]
+//
+// A condition is a selector, IN or NOT_IN and a list of values, where a single value needs no brackets and values
+// with spaces or punctuation are double quoted. Conditions joined by AND or OR form a condition group. Groups in
+// parentheses joined by AND form a subject set with several groups, and subject sets are separated by ';'.
+
+type scsToken struct {
+	text string
+	// quoted strings are always values, never keywords or punctuation
+	quoted bool
+}
+
+type scsParser struct {
+	tokens []scsToken
+	pos    int
+}
+
+// ParseSubjectConditionExpression parses subject sets from a condition expression
+func ParseSubjectConditionExpression(expr string) ([]*policy.SubjectSet, error) {
+	tokens, err := tokenizeSubjectConditionExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition expression")
+	}
+	p := &scsParser{tokens: tokens}
+
+	var sets []*policy.SubjectSet
+	for {
+		set, err := p.parseSubjectSet()
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+		if p.done() {
+			return sets, nil
+		}
+		if !p.accept(";") {
+			return nil, fmt.Errorf("expected AND, OR or ';' but got '%s'", p.peek().text)
+		}
+		if p.done() {
+			return sets, nil
+		}
+	}
+}
+
+func tokenizeSubjectConditionExpression(expr string) ([]scsToken, error) {
+	var tokens []scsToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[],;", r):
+			tokens = append(tokens, scsToken{text: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated quoted value %s", string(runes[i:]))
+			}
+			s, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value %s: %w", string(runes[i:end+1]), err)
+			}
+			tokens = append(tokens, scsToken{text: s, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`(),;`, runes[end]) {
+				c := runes[end]
+				if c != '[' && c != ']' && c != '"' {
+					end++
+					continue
+				}
+				// brackets and quotes end a word, but selectors keep their indexes and quoted keys, as in
+				// .groups[] or ."key.with.dots"
+				if r != '.' || c == ']' {
+					break
+				}
+				closing := ']'
+				if c == '"' {
+					closing = '"'
+				}
+				next := end + 1
+				for next < len(runes) && runes[next] != closing {
+					next++
+				}
+				if next >= len(runes) {
+					return nil, fmt.Errorf("unterminated selector %s", string(runes[i:]))
+				}
+				end = next + 1
+			}
+			tokens = append(tokens, scsToken{text: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (t scsToken) isPunctuation() bool {
+	return !t.quoted && len(t.text) == 1 && strings.Contains("()[],;", t.text)
+}
+
+func (p *scsParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *scsParser) peek() scsToken {
+	if p.done() {
+		return scsToken{text: "end of expression"}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the given punctuation
+func (p *scsParser) accept(punct string) bool {
+	if t := p.peek(); !p.done() && !t.quoted && t.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// acceptBoolean consumes a following AND or OR
+func (p *scsParser) acceptBoolean() (string, bool) {
+	t := p.peek()
+	if p.done() || t.quoted {
+		return "", false
+	}
+	op := strings.ToUpper(t.text)
+	if op != ConditionBooleanTypeAnd && op != ConditionBooleanTypeOr {
+		return "", false
+	}
+	p.pos++
+	return op, true
+}
+
+// A subject set is either conditions joined by one boolean operator, forming a single group, or groups joined by
+// AND where at least one is in parentheses. Bare conditions next to groups are groups of their own.
+func (p *scsParser) parseSubjectSet() (*policy.SubjectSet, error) {
+	var (
+		groups    []*policy.ConditionGroup
+		bare      []*policy.Condition
+		operators = map[string]bool{}
+	)
+	for {
+		if p.accept("(") {
+			g, err := p.parseConditionGroup(")")
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, g)
+		} else {
+			c, err := p.parseCondition()
+			if err != nil {
+				return nil, err
+			}
+			bare = append(bare, c)
+			groups = append(groups, &policy.ConditionGroup{
+				Conditions:      []*policy.Condition{c},
+				BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND,
+			})
+		}
+		op, ok := p.acceptBoolean()
+		if !ok {
+			break
+		}
+		operators[op] = true
+	}
+
+	if len(bare) == len(groups) {
+		// no parentheses, so all conditions are one group
+		if len(operators) > 1 {
+			return nil, fmt.Errorf("cannot mix AND and OR without parentheses, i.e. '(.a IN [x] OR .b IN [y]) AND .c IN [z]'")
+		}
+		op := ConditionBooleanTypeAnd
+		for o := range operators {
+			op = o
+		}
+		groups = []*policy.ConditionGroup{{Conditions: bare, BooleanOperator: GetConditionBooleanTypeFromChoice(op)}}
+	} else if operators[ConditionBooleanTypeOr] {
+		return nil, fmt.Errorf("condition groups of a subject set can only be joined by AND, separate subject sets with ';'")
+	}
+	return &policy.SubjectSet{ConditionGroups: groups}, nil
+}
+
+// parseConditionGroup parses conditions joined by a single boolean operator up to the closing token
+func (p *scsParser) parseConditionGroup(closing string) (*policy.ConditionGroup, error) {
+	op := ""
+	g := &policy.ConditionGroup{}
+	for {
+		c, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		g.Conditions = append(g.Conditions, c)
+		if p.accept(closing) {
+			break
+		}
+		next, ok := p.acceptBoolean()
+		if !ok {
+			return nil, fmt.Errorf("expected AND, OR or '%s' but got '%s'", closing, p.peek().text)
+		}
+		if op != "" && next != op {
+			return nil, fmt.Errorf("cannot mix AND and OR within one condition group")
+		}
+		op = next
+	}
+	if op == "" {
+		op = ConditionBooleanTypeAnd
+	}
+	g.BooleanOperator = GetConditionBooleanTypeFromChoice(op)
+	return g, nil
+}
+
+func (p *scsParser) parseCondition() (*policy.Condition, error) {
+	selector := p.peek()
+	if p.done() || selector.isPunctuation() {
+		return nil, fmt.Errorf("expected a selector such as '.org.department' but got '%s'", selector.text)
+	}
+	p.pos++
+
+	t := p.peek()
+	if p.done() || t.quoted {
+		return nil, fmt.Errorf("expected IN or NOT_IN after '%s' but got '%s'", selector.text, t.text)
+	}
+	op := GetSubjectMappingOperatorFromChoice(strings.ToUpper(t.text))
+	if op == policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_UNSPECIFIED {
+		return nil, fmt.Errorf("expected IN or NOT_IN after '%s' but got '%s'", selector.text, t.text)
+	}
+	p.pos++
+
+	c := &policy.Condition{SubjectExternalField: selector.text, Operator: op}
+	if !p.accept("[") {
+		v := p.peek()
+		if p.done() || v.isPunctuation() {
+			return nil, fmt.Errorf("expected a value or [values] after '%s %s' but got '%s'", selector.text, t.text, v.text)
+		}
+		p.pos++
+		c.SubjectExternalValues = []string{v.text}
+		return c, nil
+	}
+	for {
+		v := p.peek()
+		if p.done() || v.isPunctuation() {
+			return nil, fmt.Errorf("expected a value of '%s' but got '%s'", selector.text, v.text)
+		}
+		p.pos++
+		c.SubjectExternalValues = append(c.SubjectExternalValues, v.text)
+		if p.accept("]") {
+			return c, nil
+		}
+		if !p.accept(",") {
+			return nil, fmt.Errorf("expected ',' or ']' after value '%s' but got '%s'", v.text, p.peek().text)
+		}
+	}
+}
+
+// FormatSubjectSets prints subject sets as a condition expression, which parses back into the same subject sets. Only
+// the boolean operator of a group with a single condition, where it makes no difference, reads back as AND.
+func FormatSubjectSets(ss []*policy.SubjectSet) string {
+	sets := make([]string, 0, len(ss))
+	for _, s := range ss {
+		groups := s.GetConditionGroups()
+		if len(groups) == 1 {
+			sets = append(sets, formatConditionGroup(groups[0]))
+			continue
+		}
+		// every group is parenthesized, as bare conditions joined by AND would parse back into a single group
+		formatted := make([]string, 0, len(groups))
+		for _, g := range groups {
+			formatted = append(formatted, "("+formatConditionGroup(g)+")")
+		}
+		sets = append(sets, strings.Join(formatted, " AND "))
+	}
+	return strings.Join(sets, "; ")
+}
+
+func formatConditionGroup(g *policy.ConditionGroup) string {
+	op := GetConditionBooleanTypeChoiceFromEnum(g.GetBooleanOperator())
+	if op == "" {
+		op = ConditionBooleanTypeAnd
+	}
+	conditions := make([]string, 0, len(g.GetConditions()))
+	for _, c := range g.GetConditions() {
+		operator := GetSubjectMappingOperatorChoiceFromEnum(c.GetOperator())
+		conditions = append(conditions, FormatCondition(c.GetSubjectExternalField(), operator, c.GetSubjectExternalValues()))
+	}
+	return strings.Join(conditions, " "+op+" ")
+}
+
+// FormatCondition prints a condition of a condition expression
+func FormatCondition(selector string, operator string, values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteConditionValue(v))
+	}
+	if !strings.HasPrefix(selector, ".") || strings.ContainsAny(selector, "(),;") || strings.IndexFunc(selector, unicode.IsSpace) >= 0 {
+		selector = strconv.Quote(selector)
+	}
+	return fmt.Sprintf("%s %s [%s]", selector, operator, strings.Join(quoted, ", "))
+}
+
+// values are quoted when they would not read back as a single word
+func quoteConditionValue(v string) string {
+	upper := strings.ToUpper(v)
+	if v == "" || strings.ContainsAny(v, "()[],;\"\\") || strings.IndexFunc(v, unicode.IsSpace) >= 0 ||
+		upper == ConditionBooleanTypeAnd || upper == ConditionBooleanTypeOr {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// the subject sets of an expression as condition groups with readable operators, for comparison
+type exprGroup struct {
+	op         string
+	conditions []exprCondition
+}
+
+type exprCondition struct {
+	selector string
+	operator string
+	values   []string
+}
+
+func readableSubjectSets(ss []*policy.SubjectSet) [][]exprGroup {
+	sets := [][]exprGroup{}
+	for _, s := range ss {
+		groups := []exprGroup{}
+		for _, g := range s.GetConditionGroups() {
+			group := exprGroup{op: GetConditionBooleanTypeChoiceFromEnum(g.GetBooleanOperator())}
+			for _, c := range g.GetConditions() {
+				group.conditions = append(group.conditions, exprCondition{
+					selector: c.GetSubjectExternalField(),
+					operator: GetSubjectMappingOperatorChoiceFromEnum(c.GetOperator()),
+					values:   c.GetSubjectExternalValues(),
+				})
+			}
+			groups = append(groups, group)
+		}
+		sets = append(sets, groups)
+	}
+	return sets
+}
+
+func in(selector string, values ...string) exprCondition {
+	return exprCondition{selector: selector, operator: SubjectMappingOperatorIn, values: values}
+}
+
+func notIn(selector string, values ...string) exprCondition {
+	return exprCondition{selector: selector, operator: SubjectMappingOperatorNotIn, values: values}
+}
+
+func and(conditions ...exprCondition) exprGroup {
+	return exprGroup{op: ConditionBooleanTypeAnd, conditions: conditions}
+}
+
+func or(conditions ...exprCondition) exprGroup {
+	return exprGroup{op: ConditionBooleanTypeOr, conditions: conditions}
+}
+
+func TestParseSubjectConditionExpression(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want [][]exprGroup
+		// the expected error, where empty is success
+		err string
+	}{
+		{
+			name: "single value without brackets",
+			expr: ".role IN admin",
+			want: [][]exprGroup{{and(in(".role", "admin"))}},
+		},
+		{
+			name: "conditions joined by AND",
+			expr: ".org.department IN [eng, ops] AND .role NOT_IN [contractor]",
+			want: [][]exprGroup{{and(in(".org.department", "eng", "ops"), notIn(".role", "contractor"))}},
+		},
+		{
+			name: "keywords in any case",
+			expr: ".a in [x] or .b not_in [y]",
+			want: [][]exprGroup{{or(in(".a", "x"), notIn(".b", "y"))}},
+		},
+		{
+			name: "parenthesized groups joined by AND",
+			expr: "(.a IN [x] OR .b IN [y]) AND .c IN [z] AND (.d IN [w])",
+			want: [][]exprGroup{{or(in(".a", "x"), in(".b", "y")), and(in(".c", "z")), and(in(".d", "w"))}},
+		},
+		{
+			name: "subject sets separated by ';'",
+			expr: ".a IN [x]; .b IN [y] OR .c IN [z];",
+			want: [][]exprGroup{{and(in(".a", "x"))}, {or(in(".b", "y"), in(".c", "z"))}},
+		},
+		{
+			name: "quoted values",
+			expr: `.name IN ["Jane Doe", "AND", "a,b", "say \"hi\""]`,
+			want: [][]exprGroup{{and(in(".name", "Jane Doe", "AND", "a,b", `say "hi"`))}},
+		},
+		{
+			name: "selectors with indexes and quoted keys",
+			expr: `.groups[] IN [eng] AND ."key.with.dots" IN [x] AND .list[0] NOT_IN [y]`,
+			want: [][]exprGroup{{and(in(".groups[]", "eng"), in(`."key.with.dots"`, "x"), notIn(".list[0]", "y"))}},
+		},
+		{
+			name: "empty",
+			expr: "  ",
+			err:  "empty condition expression",
+		},
+		{
+			name: "AND and OR without parentheses",
+			expr: ".a IN [x] AND .b IN [y] OR .c IN [z]",
+			err:  "cannot mix AND and OR without parentheses",
+		},
+		{
+			name: "AND and OR within a group",
+			expr: "(.a IN [x] AND .b IN [y] OR .c IN [z])",
+			err:  "cannot mix AND and OR within one condition group",
+		},
+		{
+			name: "groups joined by OR",
+			expr: "(.a IN [x]) OR (.b IN [y])",
+			err:  "can only be joined by AND",
+		},
+		{
+			name: "unknown operator",
+			expr: ".a CONTAINS [x]",
+			err:  "expected IN or NOT_IN after '.a' but got 'CONTAINS'",
+		},
+		{
+			name: "missing values",
+			expr: ".a IN",
+			err:  "expected a value or [values]",
+		},
+		{
+			name: "unclosed list",
+			expr: ".a IN [x, y",
+			err:  "expected ',' or ']' after value 'y'",
+		},
+		{
+			name: "unclosed group",
+			expr: "(.a IN [x] AND .b IN [y]",
+			err:  "expected AND, OR or ')'",
+		},
+		{
+			name: "unterminated quoted value",
+			expr: `.a IN ["x]`,
+			err:  "unterminated quoted value",
+		},
+		{
+			name: "unterminated selector",
+			expr: `."key IN [x]`,
+			err:  "unterminated selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss, err := ParseSubjectConditionExpression(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readableSubjectSets(ss); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+
+			// the formatted expression parses back into the same subject sets
+			formatted := FormatSubjectSets(ss)
+			reparsed, err := ParseSubjectConditionExpression(formatted)
+			if err != nil {
+				t.Fatalf("failed to parse formatted expression %q: %v", formatted, err)
+			}
+			if got := readableSubjectSets(reparsed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("formatted expression %q parsed into %v, expected %v", formatted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSubjectSets(t *testing.T) {
+	condition := func(selector string, values ...string) *policy.Condition {
+		return &policy.Condition{
+			SubjectExternalField:  selector,
+			Operator:              policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN,
+			SubjectExternalValues: values,
+		}
+	}
+	group := func(op policy.ConditionBooleanTypeEnum, conditions ...*policy.Condition) *policy.ConditionGroup {
+		return &policy.ConditionGroup{BooleanOperator: op, Conditions: conditions}
+	}
+	andOp := policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND
+	orOp := policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+
+	tests := []struct {
+		name string
+		ss   []*policy.SubjectSet
+		want string
+	}{
+		{
+			name: "single group",
+			ss:   []*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{group(orOp, condition(".a", "x"), condition(".b", "y", "z"))}}},
+			want: ".a IN [x] OR .b IN [y, z]",
+		},
+		{
+			name: "groups of single conditions",
+			ss: []*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{
+				group(andOp, condition(".a", "x")), group(andOp, condition(".b", "y")),
+			}}},
+			want: "(.a IN [x]) AND (.b IN [y])",
+		},
+		{
+			name: "several subject sets",
+			ss: []*policy.SubjectSet{
+				{ConditionGroups: []*policy.ConditionGroup{group(andOp, condition(".a", "x"))}},
+				{ConditionGroups: []*policy.ConditionGroup{group(orOp, condition(".b", "y"), condition(".c", "z")), group(andOp, condition(".d", "w"))}},
+			},
+			want: ".a IN [x]; (.b IN [y] OR .c IN [z]) AND (.d IN [w])",
+		},
+		{
+			name: "values and selectors that need quotes",
+			ss: []*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{
+				group(andOp, condition("role", "Jane Doe", "or", "", "a;b"), condition(".a b", "x")),
+			}}},
+			want: `"role" IN ["Jane Doe", "or", "", "a;b"] AND ".a b" IN [x]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSubjectSets(tt.ss)
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			reparsed, err := ParseSubjectConditionExpression(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := readableSubjectSets(tt.ss); !reflect.DeepEqual(readableSubjectSets(reparsed), want) {
+				t.Errorf("%q parsed into %v, expected %v", got, readableSubjectSets(reparsed), want)
+			}
+		})
+	}
+}
@@ -6,6 +6,11 @@ import (
 	"github.com/opentdf/platform/protocol/go/policy/subjectmapping"
 )
 
+const (
+	ConditionBooleanTypeAnd = "AND"
+	ConditionBooleanTypeOr  = "OR"
+)
+
 func (h Handler) GetSubjectConditionSet(id string) (*policy.SubjectConditionSet, error) {
 	resp, err := h.sdk.SubjectMapping.GetSubjectConditionSet(h.ctx, &subjectmapping.GetSubjectConditionSetRequest{
 		Id: id,
@@ -27,12 +32,17 @@ func (h Handler) ListSubjectConditionSets() ([]*policy.SubjectConditionSet, erro
 
 // Creates and returns the created subject condition set
 func (h Handler) CreateSubjectConditionSet(ss []*policy.SubjectSet, metadata *common.MetadataMutable) (*policy.SubjectConditionSet, error) {
-	resp, err := h.sdk.SubjectMapping.CreateSubjectConditionSet(h.ctx, &subjectmapping.CreateSubjectConditionSetRequest{
+	req := &subjectmapping.CreateSubjectConditionSetRequest{
 		SubjectConditionSet: &subjectmapping.SubjectConditionSetCreate{
 			SubjectSets: ss,
 			Metadata:    metadata,
 		},
-	})
+	}
+	if h.DryRun {
+		return nil, dryRun("CreateSubjectConditionSet", req, nil, nil)
+	}
+
+	resp, err := h.sdk.SubjectMapping.CreateSubjectConditionSet(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +51,18 @@ func (h Handler) CreateSubjectConditionSet(ss []*policy.SubjectSet, metadata *co
 
 // Updates and returns the updated subject condition set
 func (h Handler) UpdateSubjectConditionSet(id string, ss []*policy.SubjectSet, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.SubjectConditionSet, error) {
-	_, err := h.sdk.SubjectMapping.UpdateSubjectConditionSet(h.ctx, &subjectmapping.UpdateSubjectConditionSetRequest{
+	req := &subjectmapping.UpdateSubjectConditionSetRequest{
 		Id:                     id,
 		SubjectSets:            ss,
 		Metadata:               metadata,
 		MetadataUpdateBehavior: behavior,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetSubjectConditionSet(id)
+		return nil, dryRun("UpdateSubjectConditionSet", req, current, err)
+	}
+
+	_, err := h.sdk.SubjectMapping.UpdateSubjectConditionSet(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +70,36 @@ func (h Handler) UpdateSubjectConditionSet(id string, ss []*policy.SubjectSet, m
 }
 
 func (h Handler) DeleteSubjectConditionSet(id string) error {
-	_, err := h.sdk.SubjectMapping.DeleteSubjectConditionSet(h.ctx, &subjectmapping.DeleteSubjectConditionSetRequest{
+	req := &subjectmapping.DeleteSubjectConditionSetRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetSubjectConditionSet(id)
+		return withDependents(dryRun("DeleteSubjectConditionSet", req, current, err), h.SubjectConditionSetDependents, id)
+	}
+
+	_, err := h.sdk.SubjectMapping.DeleteSubjectConditionSet(h.ctx, req)
 	return err
 }
+
+func GetConditionBooleanTypeFromChoice(readable string) policy.ConditionBooleanTypeEnum {
+	switch readable {
+	case ConditionBooleanTypeAnd:
+		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND
+	case ConditionBooleanTypeOr:
+		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+	default:
+		return policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_UNSPECIFIED
+	}
+}
+
+func GetConditionBooleanTypeChoiceFromEnum(enum policy.ConditionBooleanTypeEnum) string {
+	switch enum {
+	case policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND:
+		return ConditionBooleanTypeAnd
+	case policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR:
+		return ConditionBooleanTypeOr
+	default:
+		return ""
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/opentdf/platform/protocol/go/common"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/subjectmapping"
@@ -14,6 +16,11 @@ const (
 
 var SubjectMappingOperatorEnumChoices = []string{SubjectMappingOperatorIn, SubjectMappingOperatorNotIn, SubjectMappingOperatorUnspecified}
 
+const (
+	SubjectMappingActionDecrypt  = "DECRYPT"
+	SubjectMappingActionTransmit = "TRANSMIT"
+)
+
 func (h Handler) GetSubjectMapping(id string) (*policy.SubjectMapping, error) {
 	resp, err := h.sdk.SubjectMapping.GetSubjectMapping(h.ctx, &subjectmapping.GetSubjectMappingRequest{
 		Id: id,
@@ -29,13 +36,18 @@ func (h Handler) ListSubjectMappings() ([]*policy.SubjectMapping, error) {
 
 // Creates and returns the created subject mapping
 func (h Handler) CreateNewSubjectMapping(attrValId string, actions []*policy.Action, existingSCSId string, newScs *subjectmapping.SubjectConditionSetCreate, m *common.MetadataMutable) (*policy.SubjectMapping, error) {
-	resp, err := h.sdk.SubjectMapping.CreateSubjectMapping(h.ctx, &subjectmapping.CreateSubjectMappingRequest{
+	req := &subjectmapping.CreateSubjectMappingRequest{
 		AttributeValueId:              attrValId,
 		Actions:                       actions,
 		ExistingSubjectConditionSetId: existingSCSId,
 		NewSubjectConditionSet:        newScs,
 		Metadata:                      m,
-	})
+	}
+	if h.DryRun {
+		return nil, dryRun("CreateSubjectMapping", req, nil, nil)
+	}
+
+	resp, err := h.sdk.SubjectMapping.CreateSubjectMapping(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -44,13 +56,19 @@ func (h Handler) CreateNewSubjectMapping(attrValId string, actions []*policy.Act
 
 // Updates and returns the updated subject mapping
 func (h Handler) UpdateSubjectMapping(id string, updatedSCSId string, updatedActions []*policy.Action, metadata *common.MetadataMutable, metadataBehavior common.MetadataUpdateEnum) (*policy.SubjectMapping, error) {
-	_, err := h.sdk.SubjectMapping.UpdateSubjectMapping(h.ctx, &subjectmapping.UpdateSubjectMappingRequest{
+	req := &subjectmapping.UpdateSubjectMappingRequest{
 		Id:                     id,
 		SubjectConditionSetId:  updatedSCSId,
 		Actions:                updatedActions,
 		MetadataUpdateBehavior: metadataBehavior,
 		Metadata:               metadata,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetSubjectMapping(id)
+		return nil, dryRun("UpdateSubjectMapping", req, current, err)
+	}
+
+	_, err := h.sdk.SubjectMapping.UpdateSubjectMapping(h.ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -58,9 +76,15 @@ func (h Handler) UpdateSubjectMapping(id string, updatedSCSId string, updatedAct
 }
 
 func (h Handler) DeleteSubjectMapping(id string) (*policy.SubjectMapping, error) {
-	resp, err := h.sdk.SubjectMapping.DeleteSubjectMapping(h.ctx, &subjectmapping.DeleteSubjectMappingRequest{
+	req := &subjectmapping.DeleteSubjectMappingRequest{
 		Id: id,
-	})
+	}
+	if h.DryRun {
+		current, err := h.GetSubjectMapping(id)
+		return nil, dryRun("DeleteSubjectMapping", req, current, err)
+	}
+
+	resp, err := h.sdk.SubjectMapping.DeleteSubjectMapping(h.ctx, req)
 	return resp.SubjectMapping, err
 }
 
@@ -89,3 +113,45 @@ func GetSubjectMappingOperatorChoiceFromEnum(enum policy.SubjectMappingOperatorE
 		return SubjectMappingOperatorUnspecified
 	}
 }
+
+func GetSubjectMappingActionEnumFromChoice(readable string) policy.Action_StandardAction {
+	switch strings.ToUpper(readable) {
+	case SubjectMappingActionDecrypt:
+		return policy.Action_STANDARD_ACTION_DECRYPT
+	case SubjectMappingActionTransmit:
+		return policy.Action_STANDARD_ACTION_TRANSMIT
+	default:
+		return policy.Action_STANDARD_ACTION_UNSPECIFIED
+	}
+}
+
+func GetSubjectMappingActionChoiceFromEnum(enum policy.Action_StandardAction) string {
+	switch enum {
+	case policy.Action_STANDARD_ACTION_DECRYPT:
+		return SubjectMappingActionDecrypt
+	case policy.Action_STANDARD_ACTION_TRANSMIT:
+		return SubjectMappingActionTransmit
+	default:
+		return ""
+	}
+}
+
+// Builds the full list of Actions from readable standard action choices and custom action names
+func GetFullActionsList(standardActions, customActions []string) []*policy.Action {
+	actions := []*policy.Action{}
+	for _, a := range standardActions {
+		actions = append(actions, &policy.Action{
+			Value: &policy.Action_Standard{
+				Standard: GetSubjectMappingActionEnumFromChoice(a),
+			},
+		})
+	}
+	for _, a := range customActions {
+		actions = append(actions, &policy.Action{
+			Value: &policy.Action_Custom{
+				Custom: a,
+			},
+		})
+	}
+	return actions
+}
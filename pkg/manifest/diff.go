@@ -0,0 +1,314 @@
+package manifest
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	KindNamespace           = "namespace"
+	KindAttribute           = "attribute"
+	KindAttributeValue      = "attribute value"
+	KindSubjectConditionSet = "subject condition set"
+	KindSubjectMapping      = "subject mapping"
+	KindResourceMapping     = "resource mapping"
+	KindKasRegistryEntry    = "kas registry entry"
+	KindKasGrant            = "kas grant"
+
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is a single difference between two manifests
+type Change struct {
+	Kind   string `json:"kind"`
+	Object string `json:"object"`
+	Change string `json:"change"`
+	Field  string `json:"field,omitempty"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) added(kind, object string) {
+	d.changes = append(d.changes, Change{Kind: kind, Object: object, Change: ChangeAdded})
+}
+
+func (d *differ) removed(kind, object string) {
+	d.changes = append(d.changes, Change{Kind: kind, Object: object, Change: ChangeRemoved})
+}
+
+func (d *differ) field(kind, object, field, from, to string) {
+	if from != to {
+		d.changes = append(d.changes, Change{Kind: kind, Object: object, Change: ChangeChanged, Field: field, From: from, To: to})
+	}
+}
+
+// Diff returns every difference required to turn the 'from' manifest into the 'to' manifest. Objects are
+// matched by FQN, name, URI, or content rather than by position or local subject condition set names.
+func Diff(from, to *Manifest) []Change {
+	d := &differ{changes: []Change{}}
+	d.namespaces(from.Namespaces, to.Namespaces)
+	d.attributes(from.Attributes, to.Attributes)
+	d.subjectConditionSets(from.SubjectConditionSets, to.SubjectConditionSets)
+	d.subjectMappings(from, to)
+	d.resourceMappings(from.ResourceMappings, to.ResourceMappings)
+	d.kasRegistry(from.KasRegistry, to.KasRegistry)
+	d.kasGrants(from.KasGrants, to.KasGrants)
+	return d.changes
+}
+
+// sortedKeys returns the union of the keys of both maps in sorted order
+func sortedKeys[V any](from, to map[string]V) []string {
+	keys := []string{}
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// walk visits the union of keys in sorted order. Objects sharing a key are paired in order, passing nil for
+// the side with fewer of them, so duplicates are reported as added or removed rather than collapsed.
+func walk[T any](from, to map[string][]*T, visit func(f, t *T)) {
+	for _, k := range sortedKeys(from, to) {
+		f, t := from[k], to[k]
+		for i := 0; i < max(len(f), len(t)); i++ {
+			var fi, ti *T
+			if i < len(f) {
+				fi = f[i]
+			}
+			if i < len(t) {
+				ti = t[i]
+			}
+			visit(fi, ti)
+		}
+	}
+}
+
+// index groups the objects of a list by key, keeping objects with the same key in their order
+func index[T any](list []T, key func(T) string) map[string][]*T {
+	m := map[string][]*T{}
+	for i := range list {
+		k := key(list[i])
+		m[k] = append(m[k], &list[i])
+	}
+	return m
+}
+
+func (d *differ) namespaces(from, to []Namespace) {
+	key := func(ns Namespace) string { return strings.ToLower(ns.Name) }
+	walk(index(from, key), index(to, key), func(f, t *Namespace) {
+		switch {
+		case f == nil:
+			d.added(KindNamespace, t.Name)
+		case t == nil:
+			d.removed(KindNamespace, f.Name)
+		default:
+			d.field(KindNamespace, t.Name, "active", formatActive(f.Active), formatActive(t.Active))
+			d.field(KindNamespace, t.Name, "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+		}
+	})
+}
+
+func (d *differ) attributes(from, to []Attribute) {
+	key := func(a Attribute) string { return strings.ToLower(a.Fqn()) }
+	walk(index(from, key), index(to, key), func(f, t *Attribute) {
+		switch {
+		case f == nil:
+			d.added(KindAttribute, t.Fqn())
+		case t == nil:
+			d.removed(KindAttribute, f.Fqn())
+		default:
+			fqn := t.Fqn()
+			d.field(KindAttribute, fqn, "rule", strings.ToUpper(f.Rule), strings.ToUpper(t.Rule))
+			d.field(KindAttribute, fqn, "active", formatActive(f.Active), formatActive(t.Active))
+			d.field(KindAttribute, fqn, "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+			d.values(*f, *t)
+		}
+	})
+}
+
+func (d *differ) values(from, to Attribute) {
+	key := func(v Value) string { return strings.ToLower(v.Value) }
+	fromValues, toValues := index(from.Values, key), index(to.Values, key)
+	walk(fromValues, toValues, func(f, t *Value) {
+		switch {
+		case f == nil:
+			d.added(KindAttributeValue, to.ValueFqn(t.Value))
+		case t == nil:
+			d.removed(KindAttributeValue, from.ValueFqn(f.Value))
+		default:
+			fqn := to.ValueFqn(t.Value)
+			d.field(KindAttributeValue, fqn, "active", formatActive(f.Active), formatActive(t.Active))
+			d.field(KindAttributeValue, fqn, "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+		}
+	})
+
+	// order is significant for hierarchy rules, so compare the order of the values both sides share
+	shared := func(values []Value, other map[string][]*Value) []string {
+		order := []string{}
+		for _, v := range values {
+			if _, ok := other[key(v)]; ok {
+				order = append(order, key(v))
+			}
+		}
+		return order
+	}
+	d.field(KindAttribute, to.Fqn(), "value order", "["+strings.Join(shared(from.Values, toValues), ", ")+"]", "["+strings.Join(shared(to.Values, fromValues), ", ")+"]")
+}
+
+func (d *differ) subjectConditionSets(from, to []SubjectConditionSet) {
+	key := func(scs SubjectConditionSet) string { return SubjectSetsKey(scs.SubjectSets) }
+	walk(index(from, key), index(to, key), func(f, t *SubjectConditionSet) {
+		switch {
+		case f == nil:
+			d.added(KindSubjectConditionSet, t.Name)
+		case t == nil:
+			d.removed(KindSubjectConditionSet, f.Name)
+		default:
+			d.field(KindSubjectConditionSet, t.Name, "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+		}
+	})
+}
+
+func (d *differ) subjectMappings(from, to *Manifest) {
+	key := func(m *Manifest) func(SubjectMapping) string {
+		return func(sm SubjectMapping) string {
+			scs, _ := m.SubjectConditionSetByName(sm.SubjectConditionSet)
+			return strings.ToLower(sm.AttributeValue) + " " + SubjectSetsKey(scs.SubjectSets)
+		}
+	}
+	object := func(sm *SubjectMapping) string {
+		return fmt.Sprintf("%s <- %s", sm.AttributeValue, sm.SubjectConditionSet)
+	}
+	walk(index(from.SubjectMappings, key(from)), index(to.SubjectMappings, key(to)), func(f, t *SubjectMapping) {
+		switch {
+		case f == nil:
+			d.added(KindSubjectMapping, object(t))
+		case t == nil:
+			d.removed(KindSubjectMapping, object(f))
+		default:
+			d.field(KindSubjectMapping, object(t), "actions", FormatActions(f.Actions), FormatActions(t.Actions))
+			d.field(KindSubjectMapping, object(t), "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+		}
+	})
+}
+
+func (d *differ) resourceMappings(from, to []ResourceMapping) {
+	key := func(rm ResourceMapping) string { return strings.ToLower(rm.AttributeValue) }
+	object := func(rm *ResourceMapping) string {
+		return fmt.Sprintf("%s %s", rm.AttributeValue, formatList(rm.Terms))
+	}
+	fromValues, toValues := index(from, key), index(to, key)
+	for _, k := range sortedKeys(fromValues, toValues) {
+		fromList, toList := slices.Clone(fromValues[k]), toValues[k]
+
+		// pair mappings with identical terms first, then pair any leftovers on the same value in order
+		unmatched := []*ResourceMapping{}
+		for _, rm := range toList {
+			i := slices.IndexFunc(fromList, func(other *ResourceMapping) bool { return TermsEqual(other.Terms, rm.Terms) })
+			if i < 0 {
+				unmatched = append(unmatched, rm)
+				continue
+			}
+			d.field(KindResourceMapping, object(rm), "labels", FormatLabels(fromList[i].Labels), FormatLabels(rm.Labels))
+			fromList = slices.Delete(fromList, i, i+1)
+		}
+		for i, rm := range unmatched {
+			if i < len(fromList) {
+				d.field(KindResourceMapping, rm.AttributeValue, "terms", formatList(fromList[i].Terms), formatList(rm.Terms))
+				d.field(KindResourceMapping, rm.AttributeValue, "labels", FormatLabels(fromList[i].Labels), FormatLabels(rm.Labels))
+				continue
+			}
+			d.added(KindResourceMapping, object(rm))
+		}
+		for i := len(unmatched); i < len(fromList); i++ {
+			d.removed(KindResourceMapping, object(fromList[i]))
+		}
+	}
+}
+
+func (d *differ) kasRegistry(from, to []KasRegistryEntry) {
+	key := func(kas KasRegistryEntry) string { return kas.Uri }
+	walk(index(from, key), index(to, key), func(f, t *KasRegistryEntry) {
+		switch {
+		case f == nil:
+			d.added(KindKasRegistryEntry, t.Uri)
+		case t == nil:
+			d.removed(KindKasRegistryEntry, f.Uri)
+		default:
+			d.field(KindKasRegistryEntry, t.Uri, "public key (local)", f.PublicKey.Local, t.PublicKey.Local)
+			d.field(KindKasRegistryEntry, t.Uri, "public key (remote)", f.PublicKey.Remote, t.PublicKey.Remote)
+			d.field(KindKasRegistryEntry, t.Uri, "labels", FormatLabels(f.Labels), FormatLabels(t.Labels))
+		}
+	})
+}
+
+func (d *differ) kasGrants(from, to []KasGrant) {
+	key := func(g KasGrant) string { return g.Object() }
+	walk(index(from, key), index(to, key), func(f, t *KasGrant) {
+		switch {
+		case f == nil:
+			d.added(KindKasGrant, t.Object())
+		case t == nil:
+			d.removed(KindKasGrant, f.Object())
+		}
+	})
+}
+
+// Fqn builds the attribute FQN in the same form as handlers.GetAttributeFqn
+func (a Attribute) Fqn() string {
+	return fmt.Sprintf("https://%s/attr/%s", a.Namespace, a.Name)
+}
+
+// ValueFqn builds the FQN of one of the attribute's values
+func (a Attribute) ValueFqn(value string) string {
+	return fmt.Sprintf("%s/value/%s", a.Fqn(), value)
+}
+
+// Object describes the grant as '<attribute or value FQN> -> <KAS URI>'
+func (g KasGrant) Object() string {
+	return fmt.Sprintf("%s -> %s", g.Attribute+g.Value, g.Kas)
+}
+
+// FormatLabels renders labels as a sorted '[key=value, ...]' list
+func FormatLabels(labels map[string]string) string {
+	kv := make([]string, 0, len(labels))
+	for k, v := range labels {
+		kv = append(kv, k+"="+v)
+	}
+	sort.Strings(kv)
+	return formatList(kv)
+}
+
+// FormatActions renders standard and custom actions as a single sorted list
+func FormatActions(a Actions) string {
+	actions := []string{}
+	for _, s := range a.Standard {
+		actions = append(actions, strings.ToUpper(s))
+	}
+	actions = append(actions, a.Custom...)
+	sort.Strings(actions)
+	return formatList(actions)
+}
+
+func formatActive(active *bool) string {
+	return strconv.FormatBool(active == nil || *active)
+}
+
+func formatList(values []string) string {
+	return "[" + strings.Join(values, ", ") + "]"
+}
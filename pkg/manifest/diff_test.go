@@ -0,0 +1,173 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	// a list of subject condition sets with the same content under the given names
+	engineers := func(names ...string) string {
+		s := "subjectConditionSets:\n"
+		for _, name := range names {
+			s += fmt.Sprintf(`  - name: %s
+    subjectSets:
+      - conditionGroups:
+          - booleanOperator: AND
+            conditions:
+              - subjectExternalField: .department
+                operator: IN
+                subjectExternalValues: [eng]
+`, name)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []Change
+	}{
+		{
+			name: "identical manifests",
+			from: "namespaces: [{name: example.com}]",
+			to:   "namespaces: [{name: Example.com}]",
+			want: []Change{},
+		},
+		{
+			name: "namespaces added, removed and changed",
+			from: "namespaces: [{name: a.com}, {name: b.com, labels: {team: a}}]",
+			to:   "namespaces: [{name: b.com, active: false, labels: {team: b}}, {name: c.com}]",
+			want: []Change{
+				{Kind: KindNamespace, Object: "a.com", Change: ChangeRemoved},
+				{Kind: KindNamespace, Object: "b.com", Change: ChangeChanged, Field: "active", From: "true", To: "false"},
+				{Kind: KindNamespace, Object: "b.com", Change: ChangeChanged, Field: "labels", From: "[team=a]", To: "[team=b]"},
+				{Kind: KindNamespace, Object: "c.com", Change: ChangeAdded},
+			},
+		},
+		{
+			name: "attribute rule and values",
+			from: "attributes: [{namespace: a.com, name: level, rule: any_of, values: [low, mid, {value: high, labels: {team: a}}]}]",
+			to:   "attributes: [{namespace: a.com, name: level, rule: ALL_OF, values: [low, {value: high, active: false}, top]}, {namespace: a.com, name: team, rule: ANY_OF}]",
+			want: []Change{
+				{Kind: KindAttribute, Object: "https://a.com/attr/level", Change: ChangeChanged, Field: "rule", From: "ANY_OF", To: "ALL_OF"},
+				{Kind: KindAttributeValue, Object: "https://a.com/attr/level/value/high", Change: ChangeChanged, Field: "active", From: "true", To: "false"},
+				{Kind: KindAttributeValue, Object: "https://a.com/attr/level/value/high", Change: ChangeChanged, Field: "labels", From: "[team=a]", To: "[]"},
+				{Kind: KindAttributeValue, Object: "https://a.com/attr/level/value/mid", Change: ChangeRemoved},
+				{Kind: KindAttributeValue, Object: "https://a.com/attr/level/value/top", Change: ChangeAdded},
+				{Kind: KindAttribute, Object: "https://a.com/attr/team", Change: ChangeAdded},
+			},
+		},
+		{
+			name: "value order",
+			from: "attributes: [{namespace: a.com, name: level, rule: HIERARCHY, values: [top, mid, low]}]",
+			to:   "attributes: [{namespace: a.com, name: level, rule: HIERARCHY, values: [Mid, top, bottom, low]}]",
+			want: []Change{
+				{Kind: KindAttributeValue, Object: "https://a.com/attr/level/value/bottom", Change: ChangeAdded},
+				{Kind: KindAttribute, Object: "https://a.com/attr/level", Change: ChangeChanged, Field: "value order", From: "[top, mid, low]", To: "[mid, top, low]"},
+			},
+		},
+		{
+			name: "subject condition sets matched by content rather than name",
+			from: engineers("engineers"),
+			to:   engineers("scs-1"),
+			want: []Change{},
+		},
+		{
+			name: "subject condition set labels",
+			from: engineers("engineers"),
+			to:   engineers("engineers") + "    labels: {team: a}\n",
+			want: []Change{
+				{Kind: KindSubjectConditionSet, Object: "engineers", Change: ChangeChanged, Field: "labels", From: "[]", To: "[team=a]"},
+			},
+		},
+		{
+			name: "duplicate subject condition sets",
+			from: engineers("engineers"),
+			to:   engineers("engineers", "engineers-2"),
+			want: []Change{
+				{Kind: KindSubjectConditionSet, Object: "engineers-2", Change: ChangeAdded},
+			},
+		},
+		{
+			name: "subject mappings",
+			from: engineers("engineers") + `subjectMappings:
+  - {attributeValue: https://a.com/attr/level/value/high, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+  - {attributeValue: https://a.com/attr/level/value/low, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+`,
+			to: engineers("scs-1") + `subjectMappings:
+  - {attributeValue: https://a.com/attr/level/value/high, subjectConditionSet: scs-1, actions: {standard: [decrypt, TRANSMIT], custom: [audit]}}
+  - {attributeValue: https://a.com/attr/level/value/mid, subjectConditionSet: scs-1, actions: {standard: [DECRYPT]}}
+  - {attributeValue: https://a.com/attr/level/value/mid, subjectConditionSet: scs-1, actions: {standard: [DECRYPT]}}
+`,
+			want: []Change{
+				{Kind: KindSubjectMapping, Object: "https://a.com/attr/level/value/high <- scs-1", Change: ChangeChanged, Field: "actions", From: "[DECRYPT]", To: "[DECRYPT, TRANSMIT, audit]"},
+				{Kind: KindSubjectMapping, Object: "https://a.com/attr/level/value/low <- engineers", Change: ChangeRemoved},
+				// duplicate mappings are each reported
+				{Kind: KindSubjectMapping, Object: "https://a.com/attr/level/value/mid <- scs-1", Change: ChangeAdded},
+				{Kind: KindSubjectMapping, Object: "https://a.com/attr/level/value/mid <- scs-1", Change: ChangeAdded},
+			},
+		},
+		{
+			name: "resource mappings paired by terms",
+			from: `resourceMappings:
+  - {attributeValue: https://a.com/attr/level/value/high, terms: [secret, classified]}
+  - {attributeValue: https://a.com/attr/level/value/high, terms: [restricted]}
+  - {attributeValue: https://a.com/attr/level/value/low, terms: [public]}
+`,
+			to: `resourceMappings:
+  - {attributeValue: https://a.com/attr/level/value/high, terms: [internal]}
+  - {attributeValue: https://a.com/attr/level/value/high, terms: [Classified, secret], labels: {team: a}}
+  - {attributeValue: https://a.com/attr/level/value/mid, terms: [internal]}
+`,
+			want: []Change{
+				{Kind: KindResourceMapping, Object: "https://a.com/attr/level/value/high [Classified, secret]", Change: ChangeChanged, Field: "labels", From: "[]", To: "[team=a]"},
+				{Kind: KindResourceMapping, Object: "https://a.com/attr/level/value/high", Change: ChangeChanged, Field: "terms", From: "[restricted]", To: "[internal]"},
+				{Kind: KindResourceMapping, Object: "https://a.com/attr/level/value/low [public]", Change: ChangeRemoved},
+				{Kind: KindResourceMapping, Object: "https://a.com/attr/level/value/mid [internal]", Change: ChangeAdded},
+			},
+		},
+		{
+			name: "KAS registry and grants",
+			from: `kasRegistry:
+  - {uri: https://kas.a.com, publicKey: {remote: https://kas.a.com/key}}
+  - {uri: https://kas.b.com, publicKey: {local: key}}
+kasGrants:
+  - {kas: https://kas.a.com, attribute: https://a.com/attr/level}
+`,
+			to: `kasRegistry:
+  - {uri: https://kas.a.com, publicKey: {local: key}, labels: {team: a}}
+kasGrants:
+  - {kas: https://kas.a.com, value: https://a.com/attr/level/value/high}
+`,
+			want: []Change{
+				{Kind: KindKasRegistryEntry, Object: "https://kas.a.com", Change: ChangeChanged, Field: "public key (local)", From: "", To: "key"},
+				{Kind: KindKasRegistryEntry, Object: "https://kas.a.com", Change: ChangeChanged, Field: "public key (remote)", From: "https://kas.a.com/key", To: ""},
+				{Kind: KindKasRegistryEntry, Object: "https://kas.a.com", Change: ChangeChanged, Field: "labels", From: "[]", To: "[team=a]"},
+				{Kind: KindKasRegistryEntry, Object: "https://kas.b.com", Change: ChangeRemoved},
+				{Kind: KindKasGrant, Object: "https://a.com/attr/level -> https://kas.a.com", Change: ChangeRemoved},
+				{Kind: KindKasGrant, Object: "https://a.com/attr/level/value/high -> https://kas.a.com", Change: ChangeAdded},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := Parse([]byte(tt.from))
+			if err != nil {
+				t.Fatal(err)
+			}
+			to, err := Parse([]byte(tt.to))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Diff(from, to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected changes\n%v\ngot\n%v", tt.want, got)
+			}
+			if got := Diff(to, to); len(got) != 0 {
+				t.Errorf("expected no changes against itself, got %v", got)
+			}
+		})
+	}
+}
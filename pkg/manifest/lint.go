@@ -0,0 +1,263 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+
+	LintAttributeWithoutValues    = "attribute-without-values"
+	LintHierarchySingleValue      = "hierarchy-single-value"
+	LintInactiveValueReferenced   = "inactive-value-referenced"
+	LintUnknownReference          = "unknown-reference"
+	LintUnusedSubjectConditionSet = "unused-subject-condition-set"
+	LintDuplicateResourceTerm     = "duplicate-resource-term"
+	LintValueWithoutKasGrant      = "value-without-kas-grant"
+	LintKasEmptyKey               = "kas-empty-key"
+)
+
+// Severities from the most to the least severe
+var Severities = []string{SeverityError, SeverityWarning, SeverityInfo}
+
+// Finding is a single problem reported by Lint
+type Finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Kind     string `json:"kind"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+}
+
+// SeverityAtLeast reports whether the severity is as or more severe than the threshold
+func SeverityAtLeast(severity, threshold string) bool {
+	return severityRank(severity) <= severityRank(threshold)
+}
+
+// FailsOn reports whether any finding is as or more severe than the threshold
+func FailsOn(findings []Finding, threshold string) bool {
+	for _, f := range findings {
+		if SeverityAtLeast(f.Severity, threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(severity, s) {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+type linter struct {
+	m        *Manifest
+	findings []Finding
+}
+
+func (l *linter) report(severity, rule, kind, object, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		Severity: severity,
+		Rule:     rule,
+		Kind:     kind,
+		Object:   object,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Lint checks a manifest for common mistakes that are valid policy but likely unintended, such as mappings to
+// inactive values or values no KAS is granted to. Findings are ordered by severity.
+func Lint(m *Manifest) []Finding {
+	l := &linter{m: m, findings: []Finding{}}
+	values := l.attributes()
+	l.mappings(values)
+	l.resourceTerms()
+	l.kasRegistry()
+	l.kasGrants(values)
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return severityRank(l.findings[i].Severity) < severityRank(l.findings[j].Severity)
+	})
+	return l.findings
+}
+
+type lintValue struct {
+	fqn    string
+	active bool
+}
+
+// attributes checks value counts and returns the values of all attributes keyed by lower-cased FQN. A value is
+// only active when its attribute and namespace are too, as deactivation cascades on the platform.
+func (l *linter) attributes() map[string]lintValue {
+	inactiveNs := map[string]bool{}
+	for _, ns := range l.m.Namespaces {
+		inactiveNs[strings.ToLower(ns.Name)] = !isActive(ns.Active)
+	}
+
+	values := map[string]lintValue{}
+	for _, a := range l.m.Attributes {
+		switch {
+		case len(a.Values) == 0:
+			l.report(SeverityWarning, LintAttributeWithoutValues, KindAttribute, a.Fqn(),
+				"attribute has no values, so it cannot be mapped or used in a TDF")
+		case len(a.Values) == 1 && strings.EqualFold(a.Rule, "HIERARCHY"):
+			l.report(SeverityWarning, LintHierarchySingleValue, KindAttribute, a.Fqn(),
+				"HIERARCHY attribute has a single value, so there is no order to compare; use ANY_OF or ALL_OF")
+		}
+		attrActive := isActive(a.Active) && !inactiveNs[strings.ToLower(a.Namespace)]
+		for _, v := range a.Values {
+			fqn := a.ValueFqn(v.Value)
+			values[strings.ToLower(fqn)] = lintValue{fqn: fqn, active: attrActive && isActive(v.Active)}
+		}
+	}
+	return values
+}
+
+// mappings checks the values and subject condition sets referenced by subject and resource mappings
+func (l *linter) mappings(values map[string]lintValue) {
+	subjectRefs := map[string]int{}
+	resourceRefs := map[string]int{}
+	usedScs := map[string]bool{}
+
+	for _, sm := range l.m.SubjectMappings {
+		object := sm.AttributeValue + " <- " + sm.SubjectConditionSet
+		if _, ok := values[strings.ToLower(sm.AttributeValue)]; !ok {
+			l.report(SeverityError, LintUnknownReference, KindSubjectMapping, object,
+				"attribute value %s is not defined in the policy", sm.AttributeValue)
+		}
+		subjectRefs[strings.ToLower(sm.AttributeValue)]++
+		if _, ok := l.m.SubjectConditionSetByName(sm.SubjectConditionSet); !ok {
+			l.report(SeverityError, LintUnknownReference, KindSubjectMapping, object,
+				"subject condition set %q is not defined in the policy", sm.SubjectConditionSet)
+		}
+		usedScs[sm.SubjectConditionSet] = true
+	}
+	for _, rm := range l.m.ResourceMappings {
+		if _, ok := values[strings.ToLower(rm.AttributeValue)]; !ok {
+			l.report(SeverityError, LintUnknownReference, KindResourceMapping, rm.AttributeValue+" "+formatList(rm.Terms),
+				"attribute value %s is not defined in the policy", rm.AttributeValue)
+		}
+		resourceRefs[strings.ToLower(rm.AttributeValue)]++
+	}
+
+	for _, a := range l.m.Attributes {
+		for _, v := range a.Values {
+			key := strings.ToLower(a.ValueFqn(v.Value))
+			value := values[key]
+			if value.active || subjectRefs[key]+resourceRefs[key] == 0 {
+				continue
+			}
+			l.report(SeverityError, LintInactiveValueReferenced, KindAttributeValue, value.fqn,
+				"inactive value is still referenced by %d subject mapping(s) and %d resource mapping(s)",
+				subjectRefs[key], resourceRefs[key])
+		}
+	}
+
+	for _, scs := range l.m.SubjectConditionSets {
+		if !usedScs[scs.Name] {
+			l.report(SeverityWarning, LintUnusedSubjectConditionSet, KindSubjectConditionSet, scs.Name,
+				"subject condition set is not used by any subject mapping")
+		}
+	}
+}
+
+// resourceTerms finds terms mapped to more than one value, which tag a resource with all of them
+func (l *linter) resourceTerms() {
+	var terms []string
+	valuesByTerm := map[string][]string{}
+	for _, rm := range l.m.ResourceMappings {
+		for _, t := range rm.Terms {
+			key := strings.ToLower(strings.TrimSpace(t))
+			if _, ok := valuesByTerm[key]; !ok {
+				terms = append(terms, key)
+			}
+			if !containsFold(valuesByTerm[key], rm.AttributeValue) {
+				valuesByTerm[key] = append(valuesByTerm[key], rm.AttributeValue)
+			}
+		}
+	}
+	for _, t := range terms {
+		if values := valuesByTerm[t]; len(values) > 1 {
+			l.report(SeverityWarning, LintDuplicateResourceTerm, KindResourceMapping, t,
+				"term is mapped to %d values %s", len(values), formatList(values))
+		}
+	}
+}
+
+func (l *linter) kasRegistry() {
+	for _, kas := range l.m.KasRegistry {
+		if strings.TrimSpace(kas.PublicKey.Local) == "" && strings.TrimSpace(kas.PublicKey.Remote) == "" {
+			l.report(SeverityError, LintKasEmptyKey, KindKasRegistryEntry, kas.Uri,
+				"KAS has neither a local nor a remote public key, so clients cannot encrypt to it")
+		}
+	}
+}
+
+// kasGrants checks grant references and finds active values that no KAS is granted to, directly or through their
+// attribute, so encrypting with them falls back to the default KAS
+func (l *linter) kasGrants(values map[string]lintValue) {
+	registered := map[string]bool{}
+	for _, kas := range l.m.KasRegistry {
+		registered[strings.ToLower(kas.Uri)] = true
+	}
+	attributes := map[string]bool{}
+	for _, a := range l.m.Attributes {
+		attributes[strings.ToLower(a.Fqn())] = true
+	}
+	// attribute and value FQNs granted to a KAS
+	granted := map[string]bool{}
+	for _, g := range l.m.KasGrants {
+		// a manifest without a registry may grant KAS already registered on the platform
+		if len(l.m.KasRegistry) > 0 && !registered[strings.ToLower(g.Kas)] {
+			l.report(SeverityError, LintUnknownReference, KindKasGrant, g.Object(),
+				"KAS %s is not registered in the policy", g.Kas)
+		}
+		if g.Attribute != "" {
+			if !attributes[strings.ToLower(g.Attribute)] {
+				l.report(SeverityError, LintUnknownReference, KindKasGrant, g.Object(),
+					"attribute %s is not defined in the policy", g.Attribute)
+			}
+			granted[strings.ToLower(g.Attribute)] = true
+		}
+		if g.Value != "" {
+			if _, ok := values[strings.ToLower(g.Value)]; !ok {
+				l.report(SeverityError, LintUnknownReference, KindKasGrant, g.Object(),
+					"attribute value %s is not defined in the policy", g.Value)
+			}
+			granted[strings.ToLower(g.Value)] = true
+		}
+	}
+
+	for _, a := range l.m.Attributes {
+		if granted[strings.ToLower(a.Fqn())] {
+			continue
+		}
+		for _, v := range a.Values {
+			fqn := a.ValueFqn(v.Value)
+			if values[strings.ToLower(fqn)].active && !granted[strings.ToLower(fqn)] {
+				l.report(SeverityInfo, LintValueWithoutKasGrant, KindAttributeValue, fqn,
+					"no KAS is granted to the value or its attribute")
+			}
+		}
+	}
+}
+
+func isActive(active *bool) bool {
+	return active == nil || *active
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintKasGrants(t *testing.T) {
+	m, err := Decode([]byte(`
+namespaces:
+  - name: example.com
+attributes:
+  - namespace: example.com
+    name: level
+    rule: ANY_OF
+    values: [low, high]
+  - namespace: example.com
+    name: team
+    rule: ANY_OF
+    values: [eng, ops]
+kasRegistry:
+  - uri: https://kas.example.com
+    publicKey:
+      remote: https://kas.example.com/kas/v2/kas_public_key
+kasGrants:
+  - kas: https://kas.example.com
+    attribute: https://example.com/attr/level
+  - kas: https://kas.example.com
+    value: https://example.com/attr/team/value/eng
+  - kas: https://kas.example.com
+    attribute: https://example.com/attr/missing
+  - kas: https://kas.example.com
+    value: https://example.com/attr/team/value/missing
+  - kas: https://other.example.com
+    value: https://example.com/attr/team/value/eng
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type finding struct{ rule, object string }
+	var got []finding
+	for _, f := range Lint(m) {
+		got = append(got, finding{f.Rule, f.Object})
+	}
+	want := []finding{
+		{LintUnknownReference, "https://example.com/attr/missing -> https://kas.example.com"},
+		{LintUnknownReference, "https://example.com/attr/team/value/missing -> https://kas.example.com"},
+		{LintUnknownReference, "https://example.com/attr/team/value/eng -> https://other.example.com"},
+		// the values of level are granted through their attribute, and eng directly
+		{LintValueWithoutKasGrant, "https://example.com/attr/team/value/ops"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected findings %v, got %v", want, got)
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	const scs = `subjectConditionSets:
+  - name: engineers
+    subjectSets:
+      - conditionGroups:
+          - booleanOperator: AND
+            conditions:
+              - subjectExternalField: .department
+                operator: IN
+                subjectExternalValues: [eng]
+`
+
+	tests := []struct {
+		name     string
+		rule     string
+		manifest string
+		// objects of the findings of the rule
+		want []string
+	}{
+		{
+			name: "attribute without values",
+			rule: LintAttributeWithoutValues,
+			manifest: `attributes:
+  - {namespace: example.com, name: level, rule: ANY_OF}
+  - {namespace: example.com, name: team, rule: ANY_OF, values: [eng]}
+`,
+			want: []string{"https://example.com/attr/level"},
+		},
+		{
+			name: "hierarchy with a single value",
+			rule: LintHierarchySingleValue,
+			manifest: `attributes:
+  - {namespace: example.com, name: level, rule: HIERARCHY, values: [top]}
+  - {namespace: example.com, name: rank, rule: hierarchy, values: [top, bottom]}
+  - {namespace: example.com, name: team, rule: ANY_OF, values: [eng]}
+`,
+			want: []string{"https://example.com/attr/level"},
+		},
+		{
+			name: "inactive values referenced directly and through their attribute or namespace",
+			rule: LintInactiveValueReferenced,
+			manifest: `namespaces:
+  - name: a.com
+  - {name: b.com, active: false}
+attributes:
+  - {namespace: a.com, name: level, rule: ANY_OF, values: [{value: low, active: false}, high, {value: unused, active: false}]}
+  - {namespace: a.com, name: team, rule: ANY_OF, active: false, values: [eng]}
+  - {namespace: b.com, name: level, rule: ANY_OF, values: [top]}
+` + scs + `subjectMappings:
+  - {attributeValue: https://a.com/attr/level/value/low, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+  - {attributeValue: https://a.com/attr/level/value/high, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+  - {attributeValue: https://b.com/attr/level/value/top, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+resourceMappings:
+  - {attributeValue: https://a.com/attr/team/value/eng, terms: [engineering]}
+`,
+			want: []string{
+				"https://a.com/attr/level/value/low",
+				"https://a.com/attr/team/value/eng",
+				"https://b.com/attr/level/value/top",
+			},
+		},
+		{
+			name: "unused subject condition set",
+			rule: LintUnusedSubjectConditionSet,
+			manifest: scs + `  - name: admins
+    subjectSets:
+      - conditionGroups:
+          - booleanOperator: OR
+            conditions:
+              - subjectExternalField: .role
+                operator: IN
+                subjectExternalValues: [admin]
+subjectMappings:
+  - {attributeValue: https://example.com/attr/level/value/high, subjectConditionSet: engineers, actions: {standard: [DECRYPT]}}
+`,
+			want: []string{"admins"},
+		},
+		{
+			name: "term mapped to several values",
+			rule: LintDuplicateResourceTerm,
+			manifest: `resourceMappings:
+  - {attributeValue: https://example.com/attr/level/value/high, terms: [secret, Classified]}
+  - {attributeValue: https://example.com/attr/level/value/low, terms: [' classified', public]}
+  - {attributeValue: https://Example.com/attr/level/value/high, terms: [secret]}
+`,
+			want: []string{"classified"},
+		},
+		{
+			name: "KAS without a public key",
+			rule: LintKasEmptyKey,
+			manifest: `kasRegistry:
+  - {uri: https://kas.a.com, publicKey: {local: '  '}}
+  - {uri: https://kas.b.com, publicKey: {remote: https://kas.b.com/key}}
+  - {uri: https://kas.c.com, publicKey: {local: key}}
+`,
+			want: []string{"https://kas.a.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Decode([]byte(tt.manifest))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range Lint(m) {
+				if f.Rule == tt.rule {
+					got = append(got, f.Object)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %s findings for %v, got %v", tt.rule, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity, threshold string
+		want                bool
+	}{
+		{SeverityError, SeverityError, true},
+		{SeverityError, SeverityInfo, true},
+		{SeverityWarning, SeverityError, false},
+		{SeverityWarning, SeverityWarning, true},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityInfo, SeverityInfo, true},
+		{"ERROR", SeverityWarning, true},
+		{"unknown", SeverityInfo, false},
+	}
+
+	for _, tt := range tests {
+		if got := SeverityAtLeast(tt.severity, tt.threshold); got != tt.want {
+			t.Errorf("expected SeverityAtLeast(%s, %s) to be %t, got %t", tt.severity, tt.threshold, tt.want, got)
+		}
+	}
+}
+
+func TestFailsOn(t *testing.T) {
+	warnings := []Finding{
+		{Severity: SeverityInfo, Rule: LintValueWithoutKasGrant},
+		{Severity: SeverityWarning, Rule: LintUnusedSubjectConditionSet},
+	}
+
+	tests := []struct {
+		threshold string
+		findings  []Finding
+		want      bool
+	}{
+		{threshold: SeverityError, findings: warnings, want: false},
+		{threshold: SeverityWarning, findings: warnings, want: true},
+		{threshold: SeverityInfo, findings: warnings, want: true},
+		{threshold: SeverityInfo, findings: []Finding{}, want: false},
+		{threshold: SeverityError, findings: append(warnings, Finding{Severity: SeverityError, Rule: LintKasEmptyKey}), want: true},
+	}
+
+	for _, tt := range tests {
+		if got := FailsOn(tt.findings, tt.threshold); got != tt.want {
+			t.Errorf("expected failing on %s for %d findings to be %t, got %t", tt.threshold, len(tt.findings), tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Manifest is a portable, declarative description of platform policy. Objects refer to each other by
+// FQN, name, or URI rather than by server-generated ids so the same document can be applied to any platform.
+type Manifest struct {
+	Namespaces           []Namespace           `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	Attributes           []Attribute           `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+	SubjectConditionSets []SubjectConditionSet `yaml:"subjectConditionSets,omitempty" json:"subjectConditionSets,omitempty"`
+	SubjectMappings      []SubjectMapping      `yaml:"subjectMappings,omitempty" json:"subjectMappings,omitempty"`
+	ResourceMappings     []ResourceMapping     `yaml:"resourceMappings,omitempty" json:"resourceMappings,omitempty"`
+	KasRegistry          []KasRegistryEntry    `yaml:"kasRegistry,omitempty" json:"kasRegistry,omitempty"`
+	KasGrants            []KasGrant            `yaml:"kasGrants,omitempty" json:"kasGrants,omitempty"`
+}
+
+type Namespace struct {
+	Name   string            `yaml:"name" json:"name"`
+	Active *bool             `yaml:"active,omitempty" json:"active,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+type Attribute struct {
+	Namespace string            `yaml:"namespace" json:"namespace"`
+	Name      string            `yaml:"name" json:"name"`
+	Rule      string            `yaml:"rule" json:"rule"`
+	Values    []Value           `yaml:"values,omitempty" json:"values,omitempty"`
+	Active    *bool             `yaml:"active,omitempty" json:"active,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// Value may be written in a manifest as a plain string or as a mapping when labels or state are needed
+type Value struct {
+	Value  string            `yaml:"value" json:"value"`
+	Active *bool             `yaml:"active,omitempty" json:"active,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// SubjectConditionSet is named locally within the manifest so subject mappings can reference it. The name
+// is not stored on the platform; existing sets are matched by their subject sets instead.
+type SubjectConditionSet struct {
+	Name        string            `yaml:"name" json:"name"`
+	SubjectSets []SubjectSet      `yaml:"subjectSets" json:"subjectSets"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+type SubjectSet struct {
+	ConditionGroups []ConditionGroup `yaml:"conditionGroups,omitempty" json:"conditionGroups,omitempty"`
+}
+
+type ConditionGroup struct {
+	BooleanOperator string      `yaml:"booleanOperator" json:"booleanOperator"`
+	Conditions      []Condition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+}
+
+type Condition struct {
+	SubjectExternalField  string   `yaml:"subjectExternalField" json:"subjectExternalField"`
+	Operator              string   `yaml:"operator" json:"operator"`
+	SubjectExternalValues []string `yaml:"subjectExternalValues,omitempty" json:"subjectExternalValues,omitempty"`
+}
+
+type SubjectMapping struct {
+	AttributeValue      string            `yaml:"attributeValue" json:"attributeValue"`
+	SubjectConditionSet string            `yaml:"subjectConditionSet" json:"subjectConditionSet"`
+	Actions             Actions           `yaml:"actions" json:"actions"`
+	Labels              map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+type Actions struct {
+	Standard []string `yaml:"standard,omitempty" json:"standard,omitempty"`
+	Custom   []string `yaml:"custom,omitempty" json:"custom,omitempty"`
+}
+
+type ResourceMapping struct {
+	AttributeValue string            `yaml:"attributeValue" json:"attributeValue"`
+	Terms          []string          `yaml:"terms" json:"terms"`
+	Labels         map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+type KasRegistryEntry struct {
+	Uri       string            `yaml:"uri" json:"uri"`
+	PublicKey PublicKey         `yaml:"publicKey" json:"publicKey"`
+	Labels    map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+type PublicKey struct {
+	Local  string `yaml:"local,omitempty" json:"local,omitempty"`
+	Remote string `yaml:"remote,omitempty" json:"remote,omitempty"`
+}
+
+// KasGrant assigns a registered KAS (by URI) to either an attribute or an attribute value (by FQN)
+type KasGrant struct {
+	Kas       string `yaml:"kas" json:"kas"`
+	Attribute string `yaml:"attribute,omitempty" json:"attribute,omitempty"`
+	Value     string `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		v.Value = node.Value
+		return nil
+	}
+	type plain Value
+	return node.Decode((*plain)(v))
+}
+
+// Values without labels or state are written back out as plain strings
+func (v Value) MarshalYAML() (interface{}, error) {
+	if v.Active == nil && len(v.Labels) == 0 {
+		return v.Value, nil
+	}
+	type plain Value
+	return plain(v), nil
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v.Active == nil && len(v.Labels) == 0 {
+		return json.Marshal(v.Value)
+	}
+	type plain Value
+	return json.Marshal(plain(v))
+}
+
+// Load reads a YAML or JSON manifest from the given path
+func Load(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse decodes a YAML or JSON manifest (JSON being a subset of YAML) and validates it
+func Parse(b []byte) (*Manifest, error) {
+	m, err := Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Decode reads a YAML or JSON manifest without validating it, so a linter can report every problem
+func Decode(b []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := yaml.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Write encodes the manifest in the given format ('yaml' or 'json')
+func Write(w io.Writer, m *Manifest, format string) error {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	case FormatYAML, "":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported manifest format %q: must be one of [%s, %s]", format, FormatYAML, FormatJSON)
+	}
+}
+
+// Validate checks that required fields are present and that local references resolve
+func (m *Manifest) Validate() error {
+	for i, ns := range m.Namespaces {
+		if ns.Name == "" {
+			return fmt.Errorf("namespaces[%d]: name is required", i)
+		}
+	}
+	for i, a := range m.Attributes {
+		if a.Namespace == "" || a.Name == "" {
+			return fmt.Errorf("attributes[%d]: namespace and name are required", i)
+		}
+		if a.Rule == "" {
+			return fmt.Errorf("attributes[%d] (%s): rule is required", i, a.Name)
+		}
+		for j, v := range a.Values {
+			if v.Value == "" {
+				return fmt.Errorf("attributes[%d] (%s): values[%d] is empty", i, a.Name, j)
+			}
+		}
+	}
+	scsNames := map[string]bool{}
+	for i, scs := range m.SubjectConditionSets {
+		if scs.Name == "" {
+			return fmt.Errorf("subjectConditionSets[%d]: name is required", i)
+		}
+		if scsNames[scs.Name] {
+			return fmt.Errorf("subjectConditionSets[%d]: duplicate name %s", i, scs.Name)
+		}
+		if len(scs.SubjectSets) == 0 {
+			return fmt.Errorf("subjectConditionSets[%d] (%s): at least one subject set is required", i, scs.Name)
+		}
+		scsNames[scs.Name] = true
+	}
+	for i, sm := range m.SubjectMappings {
+		if sm.AttributeValue == "" {
+			return fmt.Errorf("subjectMappings[%d]: attributeValue is required", i)
+		}
+		if !scsNames[sm.SubjectConditionSet] {
+			return fmt.Errorf("subjectMappings[%d]: unknown subjectConditionSet %q", i, sm.SubjectConditionSet)
+		}
+		if len(sm.Actions.Standard) == 0 && len(sm.Actions.Custom) == 0 {
+			return fmt.Errorf("subjectMappings[%d]: at least one standard or custom action is required", i)
+		}
+	}
+	for i, rm := range m.ResourceMappings {
+		if rm.AttributeValue == "" || len(rm.Terms) == 0 {
+			return fmt.Errorf("resourceMappings[%d]: attributeValue and at least one term are required", i)
+		}
+	}
+	for i, kas := range m.KasRegistry {
+		if kas.Uri == "" {
+			return fmt.Errorf("kasRegistry[%d]: uri is required", i)
+		}
+		if (kas.PublicKey.Local == "") == (kas.PublicKey.Remote == "") {
+			return fmt.Errorf("kasRegistry[%d] (%s): exactly one of publicKey.local or publicKey.remote is required", i, kas.Uri)
+		}
+	}
+	for i, g := range m.KasGrants {
+		if g.Kas == "" {
+			return fmt.Errorf("kasGrants[%d]: kas is required", i)
+		}
+		if (g.Attribute == "") == (g.Value == "") {
+			return fmt.Errorf("kasGrants[%d]: exactly one of attribute or value is required", i)
+		}
+	}
+	return nil
+}
+
+// SubjectConditionSetByName returns the locally named subject condition set, if present
+func (m *Manifest) SubjectConditionSetByName(name string) (SubjectConditionSet, bool) {
+	for _, scs := range m.SubjectConditionSets {
+		if scs.Name == name {
+			return scs, true
+		}
+	}
+	return SubjectConditionSet{}, false
+}
+
+// SubjectSetsKey is a canonical representation of subject sets used to match sets by content. Operators are
+// case-normalized and, as every list within subject sets is unordered, sets, groups, conditions and values are sorted.
+func SubjectSetsKey(sets []SubjectSet) string {
+	setKeys := make([]string, len(sets))
+	for i, s := range sets {
+		groupKeys := make([]string, len(s.ConditionGroups))
+		for j, g := range s.ConditionGroups {
+			conditionKeys := make([]string, len(g.Conditions))
+			for k, c := range g.Conditions {
+				values := slices.Clone(c.SubjectExternalValues)
+				sort.Strings(values)
+				conditionKeys[k] = jsonKey([]interface{}{c.SubjectExternalField, strings.ToUpper(c.Operator), values})
+			}
+			sort.Strings(conditionKeys)
+			groupKeys[j] = jsonKey([]interface{}{strings.ToUpper(g.BooleanOperator), conditionKeys})
+		}
+		sort.Strings(groupKeys)
+		setKeys[i] = jsonKey(groupKeys)
+	}
+	sort.Strings(setKeys)
+	return jsonKey(setKeys)
+}
+
+func jsonKey(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// LabelsEqual treats nil and empty label sets as equal
+func LabelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// TermsEqual compares resource mapping terms case-insensitively and regardless of order
+func TermsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]int{}
+	for _, t := range a {
+		seen[strings.ToLower(t)]++
+	}
+	for _, t := range b {
+		k := strings.ToLower(t)
+		if seen[k] == 0 {
+			return false
+		}
+		seen[k]--
+	}
+	return true
+}
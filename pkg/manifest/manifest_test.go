@@ -0,0 +1,225 @@
+package manifest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testManifest = `
+namespaces:
+  - name: example.com
+attributes:
+  - namespace: example.com
+    name: level
+    rule: HIERARCHY
+    values:
+      - high
+      - value: low
+        active: false
+        labels:
+          team: a
+subjectConditionSets:
+  - name: engineers
+    subjectSets:
+      - conditionGroups:
+          - booleanOperator: AND
+            conditions:
+              - subjectExternalField: .department
+                operator: IN
+                subjectExternalValues: [eng]
+subjectMappings:
+  - attributeValue: https://example.com/attr/level/value/high
+    subjectConditionSet: engineers
+    actions:
+      standard: [DECRYPT]
+resourceMappings:
+  - attributeValue: https://example.com/attr/level/value/high
+    terms: [secret]
+kasRegistry:
+  - uri: https://kas.example.com
+    publicKey:
+      remote: https://kas.example.com/kas/v2/kas_public_key
+kasGrants:
+  - kas: https://kas.example.com
+    attribute: https://example.com/attr/level
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(testManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inactive := false
+	wantValues := []Value{{Value: "high"}, {Value: "low", Active: &inactive, Labels: map[string]string{"team": "a"}}}
+	if got := m.Attributes[0].Values; !reflect.DeepEqual(got, wantValues) {
+		t.Errorf("expected values %v, got %v", wantValues, got)
+	}
+	if _, ok := m.SubjectConditionSetByName("engineers"); !ok {
+		t.Error("expected the subject condition set to be found by name")
+	}
+
+	// JSON is read as a subset of YAML
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromJSON, m) {
+		t.Errorf("expected the JSON manifest %s to parse back into %+v, got %+v", b, m, fromJSON)
+	}
+
+	if _, err := Parse([]byte("namespaces: {name: example.com}")); err == nil {
+		t.Error("expected an error for a malformed manifest")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		// replaces the first occurrence of the text in the test manifest
+		old, new string
+		err      string
+	}{
+		{name: "namespace without name", old: "- name: example.com", new: "- name: ''", err: "namespaces[0]: name is required"},
+		{name: "attribute without rule", old: "rule: HIERARCHY", new: "rule: ''", err: "attributes[0] (level): rule is required"},
+		{name: "empty value", old: "- high", new: "- ''", err: "attributes[0] (level): values[0] is empty"},
+		{name: "subject condition set without name", old: "- name: engineers", new: "- name: ''", err: "subjectConditionSets[0]: name is required"},
+		{name: "subject condition set without subject sets", old: "subjectSets:", new: "subjectSets: []\n    unused:", err: "at least one subject set is required"},
+		{name: "unknown subject condition set", old: "subjectConditionSet: engineers", new: "subjectConditionSet: admins", err: `unknown subjectConditionSet "admins"`},
+		{name: "subject mapping without actions", old: "standard: [DECRYPT]", new: "standard: []", err: "at least one standard or custom action is required"},
+		{name: "resource mapping without terms", old: "terms: [secret]", new: "terms: []", err: "resourceMappings[0]: attributeValue and at least one term are required"},
+		{name: "KAS with both keys", old: "remote:", new: "local: key\n      remote:", err: "exactly one of publicKey.local or publicKey.remote is required"},
+		{name: "grant to an attribute and a value", old: "attribute: https://example.com/attr/level", new: "attribute: https://example.com/attr/level\n    value: https://example.com/attr/level/value/high", err: "kasGrants[0]: exactly one of attribute or value is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(testManifest, tt.old) {
+				t.Fatalf("test manifest does not contain %q", tt.old)
+			}
+			_, err := Parse([]byte(strings.Replace(testManifest, tt.old, tt.new, 1)))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("duplicate subject condition set name", func(t *testing.T) {
+		m, err := Parse([]byte(testManifest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.SubjectConditionSets = append(m.SubjectConditionSets, m.SubjectConditionSets[0])
+		if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "subjectConditionSets[1]: duplicate name engineers") {
+			t.Errorf("expected a duplicate name error, got %v", err)
+		}
+	})
+}
+
+func TestValueMarshal(t *testing.T) {
+	inactive := false
+	tests := []struct {
+		name  string
+		value Value
+		yaml  string
+		json  string
+	}{
+		{
+			name:  "plain value",
+			value: Value{Value: "high"},
+			yaml:  "high\n",
+			json:  `"high"`,
+		},
+		{
+			name:  "empty labels",
+			value: Value{Value: "high", Labels: map[string]string{}},
+			yaml:  "high\n",
+			json:  `"high"`,
+		},
+		{
+			name:  "inactive value",
+			value: Value{Value: "low", Active: &inactive},
+			yaml:  "value: low\nactive: false\n",
+			json:  `{"value":"low","active":false}`,
+		},
+		{
+			name:  "labeled value",
+			value: Value{Value: "low", Labels: map[string]string{"team": "a"}},
+			yaml:  "value: low\nlabels:\n    team: a\n",
+			json:  `{"value":"low","labels":{"team":"a"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y, err := yaml.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(y) != tt.yaml {
+				t.Errorf("expected YAML %q, got %q", tt.yaml, y)
+			}
+			j, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(j) != tt.json {
+				t.Errorf("expected JSON %s, got %s", tt.json, j)
+			}
+
+			// both forms read back into the same value, with empty labels read as none
+			want := tt.value
+			if len(want.Labels) == 0 {
+				want.Labels = nil
+			}
+			for _, b := range [][]byte{y, j} {
+				var got Value
+				if err := yaml.Unmarshal(b, &got); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("expected %s to read back as %+v, got %+v", b, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSubjectSetsKey(t *testing.T) {
+	sets := func(op string, conditions ...Condition) []SubjectSet {
+		return []SubjectSet{{ConditionGroups: []ConditionGroup{{BooleanOperator: op, Conditions: conditions}}}}
+	}
+	dept := Condition{SubjectExternalField: ".department", Operator: "IN", SubjectExternalValues: []string{"eng", "ops"}}
+	role := Condition{SubjectExternalField: ".role", Operator: "NOT_IN", SubjectExternalValues: []string{"contractor"}}
+	key := SubjectSetsKey(sets("AND", dept, role))
+
+	tests := []struct {
+		name  string
+		sets  []SubjectSet
+		equal bool
+	}{
+		{name: "same sets", sets: sets("AND", dept, role), equal: true},
+		{name: "operators in another case", sets: sets("and", dept, Condition{SubjectExternalField: ".role", Operator: "not_in", SubjectExternalValues: []string{"contractor"}}), equal: true},
+		{name: "conditions in another order", sets: sets("AND", role, dept), equal: true},
+		{name: "values in another order", sets: sets("AND", Condition{SubjectExternalField: ".department", Operator: "IN", SubjectExternalValues: []string{"ops", "eng"}}, role), equal: true},
+		{name: "another boolean operator", sets: sets("OR", dept, role)},
+		{name: "another value", sets: sets("AND", Condition{SubjectExternalField: ".department", Operator: "IN", SubjectExternalValues: []string{"Eng", "ops"}}, role)},
+		{name: "another selector", sets: sets("AND", Condition{SubjectExternalField: ".dept", Operator: "IN", SubjectExternalValues: []string{"eng", "ops"}}, role)},
+		{name: "fewer conditions", sets: sets("AND", dept)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubjectSetsKey(tt.sets) == key; got != tt.equal {
+				t.Errorf("expected keys to be equal %t, got %t", tt.equal, got)
+			}
+		})
+	}
+}